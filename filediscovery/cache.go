@@ -0,0 +1,143 @@
+package filediscovery
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+)
+
+var cacheNowFunc = time.Now
+
+type (
+	// CachingFileDiscoverer is a ContextDiscoverer which remembers the files found by Discover and DiscoverContext per
+	// fileName. DiscoverAll is passed to the wrapped FileDiscoverer as it is.
+	// It is safe for concurrent use, if the wrapped FileDiscoverer is.
+	CachingFileDiscoverer struct {
+		FileDiscoverer
+		ttl        time.Duration
+		revalidate func(name string) (os.FileInfo, error)
+		mutex      sync.Mutex
+		entries    map[string]cacheEntry
+	}
+
+	// CacheOption changes the behaviour of CachingFileDiscoverer.
+	CacheOption func(c *CachingFileDiscoverer)
+
+	cacheEntry struct {
+		filePath string
+		expires  time.Time
+	}
+)
+
+// Cached returns a CachingFileDiscoverer, which remembers the files found by the given discoverer for the given ttl.
+// A ttl of zero keeps found files until they are invalidated. Files that could not be found are not remembered.
+func Cached(discoverer FileDiscoverer, ttl time.Duration, options ...CacheOption) *CachingFileDiscoverer {
+	c := &CachingFileDiscoverer{
+		FileDiscoverer: discoverer,
+		ttl:            ttl,
+		entries:        map[string]cacheEntry{},
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c
+}
+
+// WithRevalidation lets CachingFileDiscoverer check a remembered file with the given stat function, like os.Stat,
+// before it is returned. If the check fails, the file is discovered again.
+func WithRevalidation(statFunc func(name string) (os.FileInfo, error)) CacheOption {
+	return func(c *CachingFileDiscoverer) {
+		c.revalidate = statFunc
+	}
+}
+
+// Discover returns the remembered file of the given fileName, or discovers it using the wrapped FileDiscoverer.
+func (c *CachingFileDiscoverer) Discover(fileName string) (string, error) {
+	return c.DiscoverContext(context.Background(), fileName)
+}
+
+// DiscoverContext works like Discover, but passes ctx to the wrapped FileDiscoverer, if it is a ContextDiscoverer.
+func (c *CachingFileDiscoverer) DiscoverContext(ctx context.Context, fileName string) (string, error) {
+	if filePath, ok := c.lookup(fileName); ok {
+		return filePath, nil
+	}
+
+	filePath, err := c.discover(ctx, fileName)
+	if err != nil {
+		return "", err
+	}
+
+	c.store(fileName, filePath)
+
+	return filePath, nil
+}
+
+func (c *CachingFileDiscoverer) discover(ctx context.Context, fileName string) (string, error) {
+	if contextDiscoverer, ok := c.FileDiscoverer.(ContextDiscoverer); ok {
+		return contextDiscoverer.DiscoverContext(ctx, fileName)
+	}
+
+	return c.FileDiscoverer.Discover(fileName)
+}
+
+// Invalidate forgets the remembered file of the given fileName.
+func (c *CachingFileDiscoverer) Invalidate(fileName string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.entries, fileName)
+}
+
+// InvalidateAll forgets all remembered files.
+func (c *CachingFileDiscoverer) InvalidateAll() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries = map[string]cacheEntry{}
+}
+
+func (c *CachingFileDiscoverer) lookup(fileName string) (string, bool) {
+	c.mutex.Lock()
+	entry, ok := c.entries[fileName]
+	c.mutex.Unlock()
+
+	if !ok {
+		return "", false
+	}
+
+	if c.ttl > 0 && !cacheNowFunc().Before(entry.expires) {
+		c.forget(fileName, entry)
+
+		return "", false
+	}
+
+	if c.revalidate != nil {
+		if _, err := c.revalidate(entry.filePath); err != nil {
+			c.forget(fileName, entry)
+
+			return "", false
+		}
+	}
+
+	return entry.filePath, true
+}
+
+func (c *CachingFileDiscoverer) store(fileName string, filePath string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries[fileName] = cacheEntry{filePath: filePath, expires: cacheNowFunc().Add(c.ttl)}
+}
+
+// forget removes the given entry, unless it was replaced in the meantime.
+func (c *CachingFileDiscoverer) forget(fileName string, entry cacheEntry) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.entries[fileName] == entry {
+		delete(c.entries, fileName)
+	}
+}
@@ -0,0 +1,154 @@
+package filediscovery
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// countingProvider returns a provider suggesting dir/fileName, which counts its calls.
+func countingProvider(dir string, calls *int) FileLocationProvider {
+	return func(fileName string) (string, error) {
+		*calls++
+
+		return dir + "/" + fileName, nil
+	}
+}
+
+func TestCached_remembersDiscoveredFiles(t *testing.T) {
+
+	calls := 0
+	fsys := fstest.MapFS{"a/app.yml": &fstest.MapFile{}}
+	discovery := Cached(New([]FileLocationProvider{countingProvider("a", &calls)}, WithFS(fsys)), 0)
+
+	for i := 0; i < 3; i++ {
+		result, err := discovery.Discover("app.yml")
+		if err != nil || result != "a/app.yml" {
+			t.Fatalf("expected discovery.Discover to return 'a/app.yml', but got '%s', %v", result, err)
+		}
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected provider to be called once, but it was called %v times", calls)
+	}
+}
+
+func TestCached_doesNotRememberMissingFiles(t *testing.T) {
+
+	calls := 0
+	discovery := Cached(New([]FileLocationProvider{countingProvider("a", &calls)}, WithFS(fstest.MapFS{})), 0)
+
+	for i := 0; i < 2; i++ {
+		if _, err := discovery.Discover("app.yml"); err == nil {
+			t.Fatalf("expected discovery.Discover to return an error, but got nil")
+		}
+	}
+
+	if calls != 2 {
+		t.Fatalf("expected provider to be called twice, but it was called %v times", calls)
+	}
+}
+
+func TestCached_expiresAfterTTL(t *testing.T) {
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	cacheNowFunc = func() time.Time { return now }
+	t.Cleanup(func() { cacheNowFunc = time.Now })
+
+	calls := 0
+	fsys := fstest.MapFS{"a/app.yml": &fstest.MapFile{}}
+	discovery := Cached(New([]FileLocationProvider{countingProvider("a", &calls)}, WithFS(fsys)), time.Minute)
+
+	_, _ = discovery.Discover("app.yml")
+	now = now.Add(59 * time.Second)
+	_, _ = discovery.Discover("app.yml")
+
+	if calls != 1 {
+		t.Fatalf("expected provider to be called once within ttl, but it was called %v times", calls)
+	}
+
+	now = now.Add(time.Second)
+	_, _ = discovery.Discover("app.yml")
+
+	if calls != 2 {
+		t.Fatalf("expected provider to be called again after ttl, but it was called %v times", calls)
+	}
+}
+
+func TestCachingFileDiscoverer_Invalidate(t *testing.T) {
+
+	calls := 0
+	fsys := fstest.MapFS{"a/app.yml": &fstest.MapFile{}, "a/other.yml": &fstest.MapFile{}}
+	discovery := Cached(New([]FileLocationProvider{countingProvider("a", &calls)}, WithFS(fsys)), 0)
+
+	_, _ = discovery.Discover("app.yml")
+	_, _ = discovery.Discover("other.yml")
+	discovery.Invalidate("app.yml")
+	_, _ = discovery.Discover("app.yml")
+	_, _ = discovery.Discover("other.yml")
+
+	if calls != 3 {
+		t.Fatalf("expected provider to be called 3 times, but it was called %v times", calls)
+	}
+
+	discovery.InvalidateAll()
+	_, _ = discovery.Discover("app.yml")
+	_, _ = discovery.Discover("other.yml")
+
+	if calls != 5 {
+		t.Fatalf("expected provider to be called 5 times, but it was called %v times", calls)
+	}
+}
+
+func TestWithRevalidation(t *testing.T) {
+
+	fsys := fstest.MapFS{"a/app.yml": &fstest.MapFile{}, "b/app.yml": &fstest.MapFile{}}
+	providers := []Provider{
+		FileLocationsProvider(func(fileName string) ([]string, error) { return []string{"a/" + fileName, "b/" + fileName}, nil }),
+	}
+
+	discovery := Cached(NewWithProviders(providers, WithFS(fsys)), 0, WithRevalidation(func(name string) (os.FileInfo, error) {
+		return fsys.Stat(name)
+	}))
+
+	if result, _ := discovery.Discover("app.yml"); result != "a/app.yml" {
+		t.Fatalf("expected discovery.Discover to return 'a/app.yml', but got '%s'", result)
+	}
+
+	delete(fsys, "a/app.yml")
+
+	if result, _ := discovery.Discover("app.yml"); result != "b/app.yml" {
+		t.Fatalf("expected discovery.Discover to return 'b/app.yml' after revalidation, but got '%s'", result)
+	}
+}
+
+func TestCachingFileDiscoverer_concurrentUse(t *testing.T) {
+
+	fsys := fstest.MapFS{"a/app.yml": &fstest.MapFile{}}
+	providers := []FileLocationProvider{
+		func(fileName string) (string, error) { return "a/" + fileName, nil },
+	}
+	discovery := Cached(New(providers, WithFS(fsys)), time.Hour)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				if result, err := discovery.Discover("app.yml"); err != nil || result != "a/app.yml" {
+					t.Errorf("expected discovery.Discover to return 'a/app.yml', but got '%s', %v", result, err)
+				}
+
+				discovery.Invalidate("app.yml")
+			}
+		}()
+	}
+
+	wg.Wait()
+}
@@ -1,10 +1,12 @@
 package filediscovery
 
 import (
-	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 )
 
 type (
@@ -15,53 +17,528 @@ type (
 		// the first matching result will be returned. If the file could not be found and error is returned as if any other
 		// error occurs.
 		Discover(fileName string) (string, error)
+
+		// DiscoverAll tries to find the given fileName in all FileLocationProviders and returns every existing file in
+		// sequence of the providers. Files suggested by more than one provider are returned only once. If no file could
+		// be found an error is returned.
+		DiscoverAll(fileName string) ([]string, error)
+	}
+
+	// ContextDiscoverer is a FileDiscoverer which supports cancellation. It is implemented by *FileDiscovery.
+	ContextDiscoverer interface {
+		FileDiscoverer
+
+		// DiscoverContext works like Discover, but stops as soon as ctx is done.
+		DiscoverContext(ctx context.Context, fileName string) (string, error)
 	}
 
 	FileDiscovery struct {
-		fileLocationProviders []FileLocationProvider
+		fileLocationProviders []Provider
+		fileSystem            fileSystem
+		target                Target
+		noFollowSymlinks      bool
+		matcher               func(filePath string, info os.FileInfo) bool
+		createDirs            bool
+		createDirsPerm        os.FileMode
+		eager                 bool
+		observers             []Observer
+		pollInterval          time.Duration
+		abortOnProviderErr    bool
+		noExpand              bool
+	}
+
+	// attemptBatch evaluates file locations, usually by calling a single provider.
+	attemptBatch func() []Attempt
+
+	// Provider suggests possible file locations to FileDiscoverer.
+	// It is implemented by all built-in providers. Plain functions implement it by converting them to
+	// FileLocationProvider, FileLocationsProvider or FileLocationContextProvider. Use Named to give them a name.
+	Provider interface {
+		// Name returns a short name of the provider, like "working dir", used in diagnostics.
+		Name() string
+		// Describe returns a description of the file locations the provider suggests, like "~/.config/myapp".
+		Describe() string
+		// Locate returns the possible file locations for the given fileName in the sequence they should be checked.
+		Locate(fileName string) ([]string, error)
+	}
+
+	// ContextProvider is a Provider which supports cancellation.
+	ContextProvider interface {
+		Provider
+		// LocateContext works like Locate, but should stop as soon as ctx is done.
+		LocateContext(ctx context.Context, fileName string) ([]string, error)
 	}
 
 	// FileLocationProvider provides a possible file location to FileDiscoverer
 	FileLocationProvider func(fileName string) (string, error)
+
+	// FileLocationsProvider provides multiple possible file locations to FileDiscoverer
+	FileLocationsProvider func(fileName string) ([]string, error)
+
+	// FileLocationContextProvider provides multiple possible file locations to FileDiscoverer and supports cancellation.
+	FileLocationContextProvider func(ctx context.Context, fileName string) ([]string, error)
+
+	namedProvider struct {
+		name        string
+		description string
+		provider    Provider
+	}
+
+	requiredProvider struct {
+		Provider
+	}
 )
 
 // New creates a new FileDiscoverer and takes a list of FileLocationProviders which specify possible location a given file
-// will be searched in.
-func New(fileLocationProviders []FileLocationProvider) FileDiscoverer {
-	return &FileDiscovery{
-		fileLocationProviders: fileLocationProviders,
+// will be searched in. Options may be passed to change the default behaviour.
+func New(fileLocationProviders []FileLocationProvider, options ...Option) FileDiscoverer {
+	providers := make([]Provider, len(fileLocationProviders))
+	for i, fileLocationProvider := range fileLocationProviders {
+		providers[i] = fileLocationProvider
 	}
+
+	return NewWithProviders(providers, options...)
+}
+
+// NewWithProviders works like New, but takes a list of Provider. Use it to combine FileLocationProviders with
+// FileLocationsProviders, named providers or any other implementation of Provider.
+func NewWithProviders(providers []Provider, options ...Option) *FileDiscovery {
+	fd := &FileDiscovery{
+		fileLocationProviders: providers,
+		fileSystem:            osFileSystem{},
+	}
+
+	for _, option := range options {
+		option(fd)
+	}
+
+	return fd
+}
+
+// Named gives the provider a name and a description, which are used in diagnostics.
+func Named(name string, description string, provider Provider) Provider {
+	return &namedProvider{name: name, description: description, provider: provider}
+}
+
+// Name implements Provider.
+func (p *namedProvider) Name() string {
+	return p.name
+}
+
+// Describe implements Provider.
+func (p *namedProvider) Describe() string {
+	return p.description
+}
+
+// Locate implements Provider.
+func (p *namedProvider) Locate(fileName string) ([]string, error) {
+	return p.provider.Locate(fileName)
+}
+
+// LocateContext implements ContextProvider.
+func (p *namedProvider) LocateContext(ctx context.Context, fileName string) ([]string, error) {
+	return locate(ctx, p.provider, fileName)
+}
+
+// Required returns a provider, which makes the discovery fail with ErrRequired if one of the file locations suggested
+// by the given provider is rejected, instead of continuing with the next location. It is meant for file locations
+// given explicitly by the user, like a --config flag. If the provider suggests no location, the discovery continues.
+func Required(provider Provider) Provider {
+	return &requiredProvider{Provider: provider}
+}
+
+// LocateContext implements ContextProvider.
+func (p *requiredProvider) LocateContext(ctx context.Context, fileName string) ([]string, error) {
+	return locate(ctx, p.Provider, fileName)
+}
+
+func isRequired(provider Provider) bool {
+	switch p := provider.(type) {
+	case *requiredProvider:
+		return true
+	case *namedProvider:
+		return isRequired(p.provider)
+	}
+
+	return false
+}
+
+// Name implements Provider. A plain function has no name.
+func (p FileLocationProvider) Name() string {
+	return ""
+}
+
+// Describe implements Provider. A plain function has no description.
+func (p FileLocationProvider) Describe() string {
+	return ""
+}
+
+// Locate implements Provider.
+func (p FileLocationProvider) Locate(fileName string) ([]string, error) {
+	filePath, err := p(fileName)
+
+	return []string{filePath}, err
+}
+
+// Name implements Provider. A plain function has no name.
+func (p FileLocationsProvider) Name() string {
+	return ""
+}
+
+// Describe implements Provider. A plain function has no description.
+func (p FileLocationsProvider) Describe() string {
+	return ""
+}
+
+// Locate implements Provider.
+func (p FileLocationsProvider) Locate(fileName string) ([]string, error) {
+	return p(fileName)
+}
+
+// Name implements Provider. A plain function has no name.
+func (p FileLocationContextProvider) Name() string {
+	return ""
+}
+
+// Describe implements Provider. A plain function has no description.
+func (p FileLocationContextProvider) Describe() string {
+	return ""
+}
+
+// Locate implements Provider.
+func (p FileLocationContextProvider) Locate(fileName string) ([]string, error) {
+	return p(context.Background(), fileName)
+}
+
+// LocateContext implements ContextProvider.
+func (p FileLocationContextProvider) LocateContext(ctx context.Context, fileName string) ([]string, error) {
+	return p(ctx, fileName)
 }
 
 // Discover tries to find the given fileName in all FileLocationProviders. The providers are checked in given sequence.
-// the first matching result will be returned. If the file could not be found and error is returned as if any other
-// error occurs.
+// the first matching result will be returned. If the file could not be found a *NotFoundError is returned, which
+// lists every location that was checked.
 func (fd *FileDiscovery) Discover(fileName string) (string, error) {
+	return fd.DiscoverContext(context.Background(), fileName)
+}
+
+// DiscoverContext works like Discover, but passes ctx to ContextProviders and stops as soon as ctx is done.
+// In that case the returned *NotFoundError holds ctx.Err() and the locations checked so far.
+func (fd *FileDiscovery) DiscoverContext(ctx context.Context, fileName string) (string, error) {
+	result, err := fd.discoverFirst(ctx, fileName, fd.providerBatches(ctx, fileName))
+	if err != nil {
+		return "", err
+	}
+
+	return result.Path, nil
+}
+
+// DiscoverResult works like Discover, but returns details about the discovered file, like the provider which
+// suggested it and the locations that were checked before.
+func (fd *FileDiscovery) DiscoverResult(fileName string) (*Result, error) {
+	ctx := context.Background()
+
+	return fd.discoverFirst(ctx, fileName, fd.providerBatches(ctx, fileName))
+}
+
+// DiscoverAll tries to find the given fileName in all FileLocationProviders and returns every existing file in
+// sequence of the providers. Files suggested by more than one provider are returned only once. If no file could
+// be found a *NotFoundError is returned.
+func (fd *FileDiscovery) DiscoverAll(fileName string) ([]string, error) {
+	ctx := context.Background()
+	start := time.Now()
+
+	var filePaths []string
+
+	seen := map[string]bool{}
+
+	attempts, _, abortErr := fd.search(ctx, fd.providerBatches(ctx, fileName), func(attempt *Attempt) bool {
+		if fd.checkAttempt(ctx, attempt) == nil {
+			return false
+		}
+
+		key := attempt.Path
+		if absPath, err := filepath.Abs(key); err == nil {
+			key = absPath
+		}
+
+		if !seen[key] {
+			seen[key] = true
+			filePaths = append(filePaths, attempt.Path)
+		}
+
+		return false
+	})
+
+	if len(filePaths) == 0 || abortErr != nil {
+		err := &NotFoundError{FileName: fileName, Attempts: attempts, Err: abortErr}
+		fd.observeDecision(fileName, nil, err, start)
+
+		return nil, err
+	}
+
+	fd.observeDecision(fileName, filePaths, nil, start)
+
+	return filePaths, nil
+}
+
+// discoverFirst checks the file locations of the given batches in sequence and returns the first one that matches.
+func (fd *FileDiscovery) discoverFirst(ctx context.Context, fileName string, batches []attemptBatch) (*Result, error) {
+	start := time.Now()
+
+	var info os.FileInfo
+
+	attempts, found, abortErr := fd.search(ctx, batches, func(attempt *Attempt) bool {
+		info = fd.checkAttempt(ctx, attempt)
+
+		return info != nil
+	})
 
-	var possibleConfigFilePaths []string
+	if found < 0 {
+		err := &NotFoundError{FileName: fileName, Attempts: attempts, Err: abortErr}
+		fd.observeDecision(fileName, nil, err, start)
 
-	errorString := bytes.NewBufferString("")
+		return nil, err
+	}
+
+	fd.observeDecision(fileName, []string{attempts[found].Path}, nil, start)
+
+	return newResult(attempts[found], fd.providerName(attempts[found].Provider), info, attempts[:found]), nil
+}
+
+// search passes the file locations of the given batches to visit in sequence, until visit returns true or ctx is done.
+// By default a batch is only evaluated, if visit did not return true for any location of the previous batches.
+// With eager evaluation all batches are evaluated before the first location is visited.
+// It returns the evaluated attempts, the index of the attempt visit returned true for, or -1, and the reason why the
+// search was aborted, if it was.
+func (fd *FileDiscovery) search(ctx context.Context, batches []attemptBatch, visit func(attempt *Attempt) bool) ([]Attempt, int, error) {
+	var attempts []Attempt
+
+	if fd.eager {
+		for _, batch := range batches {
+			if ctx.Err() != nil {
+				break
+			}
 
-	for _, configFileProvider := range fd.fileLocationProviders {
-		possibleConfigFile, err := configFileProvider(fileName)
-		if err != nil {
-			errorString.WriteString(err.Error())
-			errorString.WriteString("\n")
+			attempts = append(attempts, batch()...)
 		}
 
-		possibleConfigFilePaths = append(possibleConfigFilePaths, possibleConfigFile)
+		return fd.visit(ctx, attempts, 0, visit)
+	}
+
+	for _, batch := range batches {
+		if ctx.Err() != nil {
+			break
+		}
+
+		start := len(attempts)
+		attempts = append(attempts, batch()...)
+
+		if visited, found, err := fd.visit(ctx, attempts, start, visit); found >= 0 || err != nil {
+			return visited, found, err
+		}
 	}
 
-	for _, configFilePath := range possibleConfigFilePaths {
-		if _, err := os.Stat(configFilePath); os.IsNotExist(err) {
-			errorString.WriteString(fmt.Sprintf("could not find config file at '%s'\n", configFilePath))
+	return attempts, -1, ctx.Err()
+}
+
+// visit passes the attempts starting at start to visit. Attempts which only carry a provider error are not visited.
+// It aborts, if ctx is done, the location of a Required provider was rejected or, if configured, a provider failed.
+func (fd *FileDiscovery) visit(ctx context.Context, attempts []Attempt, start int, visit func(attempt *Attempt) bool) ([]Attempt, int, error) {
+	for i := start; i < len(attempts); i++ {
+		if ctx.Err() != nil {
+			break
+		}
+
+		if attempts[i].ProviderErr != nil && fd.abortOnProviderErr {
+			return attempts[:i+1], -1, fmt.Errorf("%s: %w", fd.providerName(attempts[i].Provider), attempts[i].ProviderErr)
+		}
+
+		if attempts[i].ProviderErr != nil && attempts[i].Path == "" {
 			continue
 		}
 
-		if info, err := os.Stat(configFilePath); err == nil && !info.IsDir() {
-			return configFilePath, nil
+		if visit(&attempts[i]) {
+			return attempts, i, nil
+		}
+
+		if attempts[i].Err != nil && isRequired(fd.fileLocationProviders[attempts[i].Provider]) {
+			return attempts[:i+1], -1, fmt.Errorf("%w: '%s'", ErrRequired, attempts[i].Path)
 		}
 	}
 
-	return "", errors.New(errorString.String())
+	return attempts, -1, ctx.Err()
+}
+
+// providerBatches returns one batch per provider, which evaluates the file locations of fileName.
+func (fd *FileDiscovery) providerBatches(ctx context.Context, fileName string) []attemptBatch {
+	batches := make([]attemptBatch, len(fd.fileLocationProviders))
+	for i := range fd.fileLocationProviders {
+		provider := i
+		batches[i] = func() []Attempt {
+			return fd.collectProviderAttempts(ctx, provider, fileName)
+		}
+	}
+
+	return batches
+}
+
+func (fd *FileDiscovery) collectProviderAttempts(ctx context.Context, provider int, fileName string) []Attempt {
+	if fd.noExpand {
+		ctx = context.WithValue(ctx, noExpansionKey{}, true)
+	}
+
+	start := time.Now()
+	possibleFilePaths, err := locate(ctx, fd.fileLocationProviders[provider], fileName)
+	fd.observeProvider(provider, fileName, possibleFilePaths, err, start)
+	if errors.Is(err, ErrSkip) {
+		return nil
+	}
+
+	if len(possibleFilePaths) == 0 && err != nil {
+		possibleFilePaths = []string{""}
+	}
+
+	attempts := make([]Attempt, 0, len(possibleFilePaths))
+
+	for i, possibleFilePath := range possibleFilePaths {
+		attempt := Attempt{
+			Provider:     provider,
+			ProviderName: fd.fileLocationProviders[provider].Name(),
+			FileName:     fileName,
+			Path:         possibleFilePath,
+		}
+		if i == 0 {
+			attempt.ProviderErr = err
+		}
+
+		attempts = append(attempts, attempt)
+	}
+
+	return attempts
+}
+
+// providerName returns the name of the provider with the given index, or a generic name if it has none.
+func (fd *FileDiscovery) providerName(provider int) string {
+	if name := fd.fileLocationProviders[provider].Name(); name != "" {
+		return name
+	}
+
+	return fmt.Sprintf("provider %v", provider)
+}
+
+// locate calls the given provider. Providers not implementing ContextProvider cannot be cancelled, so ctx.Err() is
+// returned as soon as ctx is done, even if such a provider is still blocked.
+func locate(ctx context.Context, provider Provider, fileName string) ([]string, error) {
+	if contextProvider, ok := provider.(ContextProvider); ok {
+		return contextProvider.LocateContext(ctx, fileName)
+	}
+
+	if ctx.Done() == nil {
+		return provider.Locate(fileName)
+	}
+
+	type locateResult struct {
+		filePaths []string
+		err       error
+	}
+
+	result := make(chan locateResult, 1)
+
+	go func() {
+		filePaths, err := provider.Locate(fileName)
+		result <- locateResult{filePaths: filePaths, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-result:
+		return r.filePaths, r.err
+	}
+}
+
+// checkAttempt returns the os.FileInfo of the attempts path if it is an existing file, otherwise the reason is stored
+// in attempt.Err and nil is returned.
+func (fd *FileDiscovery) checkAttempt(ctx context.Context, attempt *Attempt) os.FileInfo {
+	start := time.Now()
+	info, err := fd.check(ctx, attempt.Path)
+	if err != nil {
+		attempt.Err = err
+		fd.observeCheck(attempt, nil, start)
+
+		return nil
+	}
+
+	fd.observeCheck(attempt, info, start)
+
+	return info
+}
+
+func (fd *FileDiscovery) check(ctx context.Context, filePath string) (os.FileInfo, error) {
+	info, err := fd.statContext(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fd.accept(filePath, info); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// accept returns the reason why the existing file location is rejected, or nil if it is accepted.
+func (fd *FileDiscovery) accept(filePath string, info os.FileInfo) error {
+	if info.Mode()&os.ModeSymlink != 0 {
+		return ErrIsSymlink
+	}
+
+	if info.IsDir() && fd.target == TargetFile {
+		return ErrIsDirectory
+	}
+
+	if !info.IsDir() && fd.target == TargetDirectory {
+		return ErrIsNotDirectory
+	}
+
+	if fd.matcher != nil && !fd.matcher(filePath, info) {
+		return ErrNotMatched
+	}
+
+	return nil
+}
+
+func (fd *FileDiscovery) stat(name string) (os.FileInfo, error) {
+	if fd.noFollowSymlinks {
+		return fd.fileSystem.Lstat(name)
+	}
+
+	return fd.fileSystem.Stat(name)
+}
+
+// statContext stats the given file, but returns ctx.Err() as soon as ctx is done, even if the stat is still blocked.
+func (fd *FileDiscovery) statContext(ctx context.Context, name string) (os.FileInfo, error) {
+	if ctx.Done() == nil {
+		return fd.stat(name)
+	}
+
+	type statResult struct {
+		info os.FileInfo
+		err  error
+	}
+
+	result := make(chan statResult, 1)
+
+	go func() {
+		info, err := fd.stat(name)
+		result <- statResult{info: info, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-result:
+		return r.info, r.err
+	}
 }
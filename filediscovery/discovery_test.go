@@ -2,12 +2,18 @@ package filediscovery
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
 	"os"
 	"path"
 	"reflect"
 	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 )
 
 func TestNew(t *testing.T) {
@@ -18,6 +24,31 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestNewWithProviders(t *testing.T) {
+
+	var newFunc func([]FileLocationProvider, ...Option) FileDiscoverer = New
+
+	if _, err := newFunc(nil).Discover("test-file"); err == nil {
+		t.Fatalf("expected discovery without providers to return an error, but got nil")
+	}
+
+	dirs := createTestDirs(t, 2)
+	defer removeTestDirs(t, dirs)
+
+	expectedPath := path.Join(dirs[1], "test-file")
+	createTestFile(t, expectedPath)
+
+	providers := []Provider{
+		FileLocationProvider(func(fileName string) (string, error) { return path.Join(dirs[0], fileName), nil }),
+		FileLocationsProvider(func(fileName string) ([]string, error) { return []string{path.Join(dirs[1], fileName)}, nil }),
+	}
+
+	result, err := NewWithProviders(providers).Discover("test-file")
+	if err != nil || result != expectedPath {
+		t.Fatalf("expected discovery.Discover to return '%s', but got '%s', %v", expectedPath, result, err)
+	}
+}
+
 func TestFileDiscovery_Discover_callsFileLocationProviders(t *testing.T) {
 
 	mock1, provider1 := newFileLocationProviderMock()
@@ -153,3 +184,465 @@ func TestFileDiscovery_Discover_ifFileWasFoundReturnsFilePath(t *testing.T) {
 		t.Fatalf("did not expect os.Remove to return an error, but got: %v", err)
 	}
 }
+
+func TestFileDiscovery_Discover_ifFileNotFoundReturnsNotFoundError(t *testing.T) {
+
+	errStub := errors.New("stub-error")
+	providers := []FileLocationProvider{
+		(&fileLocationProviderMock{}).GetFunc("", errStub),
+		(&fileLocationProviderMock{}).GetFunc(os.TempDir(), nil),
+		(&fileLocationProviderMock{}).GetFunc(path.Join(os.TempDir(), "does-not-exist"), nil),
+	}
+
+	discovery := New(providers)
+	_, err := discovery.Discover("test-file")
+
+	var notFoundErr *NotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Fatalf("expected error to be a %T, but got: %v", notFoundErr, err)
+	}
+
+	if len(notFoundErr.Attempts) != len(providers) {
+		t.Fatalf("expected %v attempts, but got %v", len(providers), len(notFoundErr.Attempts))
+	}
+
+	if notFoundErr.Attempts[0].ProviderErr != errStub {
+		t.Fatalf("expected first attempt to carry provider error %v, but got %v", errStub, notFoundErr.Attempts[0].ProviderErr)
+	}
+
+	if !errors.Is(notFoundErr.Attempts[1].Err, ErrIsDirectory) {
+		t.Fatalf("expected second attempt to be rejected as directory, but got %v", notFoundErr.Attempts[1].Err)
+	}
+
+	if !os.IsNotExist(notFoundErr.Attempts[2].Err) {
+		t.Fatalf("expected third attempt to not exist, but got %v", notFoundErr.Attempts[2].Err)
+	}
+
+	if !errors.Is(err, errStub) || !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected errors.Is to match the errors of the attempts")
+	}
+}
+
+func TestFileDiscovery_DiscoverAll_returnsAllExistingFilesInProviderSequence(t *testing.T) {
+
+	testFilename := "test-file"
+	dirs := createTestDirs(t, 3)
+	defer removeTestDirs(t, dirs)
+
+	createTestFile(t, path.Join(dirs[0], testFilename))
+	createTestFile(t, path.Join(dirs[2], testFilename))
+
+	providers := []FileLocationProvider{
+		(&fileLocationProviderMock{}).GetFunc(path.Join(dirs[2], testFilename), nil),
+		(&fileLocationProviderMock{}).GetFunc(path.Join(dirs[1], testFilename), nil),
+		(&fileLocationProviderMock{}).GetFunc(path.Join(dirs[0], testFilename), nil),
+		(&fileLocationProviderMock{}).GetFunc(path.Join(dirs[2], "..", path.Base(dirs[2]), testFilename), nil),
+	}
+
+	discovery := New(providers)
+
+	result, err := discovery.DiscoverAll(testFilename)
+	if err != nil {
+		t.Fatalf("did not expect discovery.DiscoverAll to return an error, but got: %v", err)
+	}
+
+	expected := []string{path.Join(dirs[2], testFilename), path.Join(dirs[0], testFilename)}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("expected discovery.DiscoverAll to return %v, but got %v", expected, result)
+	}
+}
+
+func TestFileDiscovery_DiscoverAll_ifFileNotFoundReturnsNotFoundError(t *testing.T) {
+
+	mock, provider := newFileLocationProviderMock()
+	discovery := New([]FileLocationProvider{provider})
+
+	result, err := discovery.DiscoverAll("test-file")
+	if result != nil {
+		t.Fatalf("expected no result, but got %v", result)
+	}
+
+	var notFoundErr *NotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Fatalf("expected error to be a %T, but got: %v", notFoundErr, err)
+	}
+
+	if !mock.WasCalled() {
+		t.Fatalf("expected mock to be called, but it was not")
+	}
+}
+
+func createTestDirs(t *testing.T, count int) []string {
+	t.Helper()
+
+	dirs := make([]string, count)
+	for i := range dirs {
+		dir, err := ioutil.TempDir("", "filediscovery-test")
+		if err != nil {
+			t.Fatalf("did not expect ioutil.TempDir to return an error, but got: %v", err)
+		}
+
+		dirs[i] = dir
+	}
+
+	return dirs
+}
+
+func removeTestDirs(t *testing.T, dirs []string) {
+	t.Helper()
+
+	for _, dir := range dirs {
+		err := os.RemoveAll(dir)
+		if err != nil {
+			t.Fatalf("did not expect os.RemoveAll to return an error, but got: %v", err)
+		}
+	}
+}
+
+func createTestFile(t *testing.T, filePath string) {
+	t.Helper()
+
+	err := ioutil.WriteFile(filePath, []byte("test"), 0666)
+	if err != nil {
+		t.Fatalf("did not expect ioutil.WriteFile to return an error, but got: %v", err)
+	}
+}
+
+func TestFileDiscovery_Discover_checksAllLocationsOfFileLocationsProvider(t *testing.T) {
+
+	testFilename := "test-file"
+	dirs := createTestDirs(t, 3)
+	defer removeTestDirs(t, dirs)
+
+	createTestFile(t, path.Join(dirs[2], testFilename))
+
+	var calledFilename string
+	locationsProvider := func(fileName string) ([]string, error) {
+		calledFilename = fileName
+		return []string{path.Join(dirs[1], fileName), path.Join(dirs[2], fileName)}, nil
+	}
+
+	providers := []Provider{
+		FileLocationProvider(func(fileName string) (string, error) { return path.Join(dirs[0], fileName), nil }),
+		FileLocationsProvider(locationsProvider),
+	}
+
+	discovery := NewWithProviders(providers)
+
+	result, err := discovery.Discover(testFilename)
+	if err != nil {
+		t.Fatalf("did not expect discovery.Discover to return an error, but got: %v", err)
+	}
+
+	if testFilename != calledFilename {
+		t.Fatalf("expected %s would have been passed to FileLocationsProvider, but it got: %s", testFilename, calledFilename)
+	}
+
+	if path.Join(dirs[2], testFilename) != result {
+		t.Fatalf("expected '%s' to match '%s'", path.Join(dirs[2], testFilename), result)
+	}
+}
+
+func TestFileDiscovery_Discover_reportsAllLocationsOfFileLocationsProvider(t *testing.T) {
+
+	errStub := errors.New("stub-error")
+	providers := []Provider{
+		FileLocationsProvider(func(fileName string) ([]string, error) {
+			return []string{"/does-not-exist/a", "/does-not-exist/b"}, nil
+		}),
+		FileLocationsProvider(func(fileName string) ([]string, error) { return nil, errStub }),
+	}
+
+	discovery := NewWithProviders(providers)
+	_, err := discovery.Discover("test-file")
+
+	var notFoundErr *NotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Fatalf("expected error to be a %T, but got: %v", notFoundErr, err)
+	}
+
+	expectedAttempts := []Attempt{
+		{Provider: 0, Path: "/does-not-exist/a"},
+		{Provider: 0, Path: "/does-not-exist/b"},
+		{Provider: 1, Path: "", ProviderErr: errStub},
+	}
+
+	if len(expectedAttempts) != len(notFoundErr.Attempts) {
+		t.Fatalf("expected %v attempts, but got %v", len(expectedAttempts), len(notFoundErr.Attempts))
+	}
+
+	for i, expected := range expectedAttempts {
+		attempt := notFoundErr.Attempts[i]
+		if expected.Provider != attempt.Provider || expected.Path != attempt.Path || expected.ProviderErr != attempt.ProviderErr {
+			t.Fatalf("expected attempt %v to be %+v, but got %+v", i, expected, attempt)
+		}
+	}
+}
+
+func TestFileDiscovery_DiscoverContext_passesContextToContextProviders(t *testing.T) {
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+
+	var calledCtx context.Context
+	provider := FileLocationContextProvider(func(ctx context.Context, fileName string) ([]string, error) {
+		calledCtx = ctx
+		return nil, nil
+	})
+
+	discovery := NewWithProviders([]Provider{provider})
+	_, _ = discovery.DiscoverContext(ctx, "test-file")
+
+	if calledCtx == nil || calledCtx.Value(ctxKey{}) != "value" {
+		t.Fatalf("expected context to be passed to provider, but got %v", calledCtx)
+	}
+}
+
+func TestFileDiscovery_DiscoverContext_stopsWhenContextIsDone(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cancellingProvider := FileLocationContextProvider(func(ctx context.Context, fileName string) ([]string, error) {
+		cancel()
+		return []string{"/does-not-exist/test-file"}, nil
+	})
+	mock, provider := newFileLocationProviderMock()
+
+	discovery := NewWithProviders([]Provider{cancellingProvider, provider})
+	_, err := discovery.DiscoverContext(ctx, "test-file")
+
+	if mock.WasCalled() {
+		t.Fatalf("expected mock not to be called after context was cancelled")
+	}
+
+	var notFoundErr *NotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Fatalf("expected error to be a %T, but got: %v", notFoundErr, err)
+	}
+
+	if notFoundErr.Err != context.Canceled {
+		t.Fatalf("expected error to hold %v, but got %v", context.Canceled, notFoundErr.Err)
+	}
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected errors.Is(err, context.Canceled) to be true")
+	}
+
+	if len(notFoundErr.Attempts) != 1 || notFoundErr.Attempts[0].Err != nil {
+		t.Fatalf("expected the collected location to be reported unchecked, but got %+v", notFoundErr.Attempts)
+	}
+}
+
+func TestFileDiscovery_DiscoverContext_doesNotWaitForBlockingProvider(t *testing.T) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	unblock := make(chan struct{})
+	defer close(unblock)
+
+	blockingProvider := FileLocationProvider(func(fileName string) (string, error) {
+		<-unblock
+		return "/does-not-exist/test-file", nil
+	})
+
+	discovery := NewWithProviders([]Provider{blockingProvider})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := discovery.DiscoverContext(ctx, "test-file")
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected errors.Is(err, context.DeadlineExceeded) to be true, but got: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected DiscoverContext to return when ctx is done, but it blocked")
+	}
+}
+
+func TestNamed(t *testing.T) {
+
+	_, plainProvider := newFileLocationProviderMock()
+	if plainProvider.Name() != "" || plainProvider.Describe() != "" {
+		t.Fatalf("expected plain function to have no name and description")
+	}
+
+	provider := Named("tmp dir", "/tmp", FileLocationProvider(func(fileName string) (string, error) {
+		return path.Join("/tmp", fileName), nil
+	}))
+
+	if provider.Name() != "tmp dir" {
+		t.Fatalf("expected name 'tmp dir', but got '%s'", provider.Name())
+	}
+
+	if provider.Describe() != "/tmp" {
+		t.Fatalf("expected description '/tmp', but got '%s'", provider.Describe())
+	}
+
+	result, err := provider.Locate("test-file")
+	if err != nil || !reflect.DeepEqual([]string{"/tmp/test-file"}, result) {
+		t.Fatalf("expected provider to locate '/tmp/test-file', but got %v, %v", result, err)
+	}
+}
+
+func TestFileDiscovery_Discover_diagnosticsUseProviderNames(t *testing.T) {
+
+	errStub := errors.New("stub-error")
+	providers := []Provider{
+		Named("failing", "", FileLocationProvider(func(fileName string) (string, error) { return "", errStub })),
+		Named("missing", "", FileLocationProvider(func(fileName string) (string, error) { return "/does-not-exist", nil })),
+	}
+
+	_, err := NewWithProviders(providers).Discover("test-file")
+
+	expectedError := "failing: stub-error\n" +
+		"could not find config file at '/does-not-exist' (missing)\n"
+
+	if err == nil || expectedError != err.Error() {
+		t.Fatalf("expected error\n%s\nbut got\n%v", expectedError, err)
+	}
+}
+
+func TestFileDiscovery_Discover_stopsAtFirstProviderWithMatch(t *testing.T) {
+
+	mock1, _ := newFileLocationProviderMock()
+	mock2, provider2 := newFileLocationProviderMock()
+
+	providers := []FileLocationProvider{
+		mock1.GetFunc("a/test-file", nil),
+		provider2,
+	}
+
+	discovery := New(providers, WithFS(fstest.MapFS{"a/test-file": &fstest.MapFile{}}))
+
+	result, err := discovery.Discover("test-file")
+	if err != nil || result != "a/test-file" {
+		t.Fatalf("expected discovery.Discover to return 'a/test-file', but got '%s', %v", result, err)
+	}
+
+	if !mock1.WasCalled() {
+		t.Fatalf("expected mock1 to be called, but it was not")
+	}
+	if mock2.WasCalled() {
+		t.Fatalf("expected mock2 not to be called, but it was")
+	}
+}
+
+func BenchmarkFileDiscovery_Discover(b *testing.B) {
+	b.Run("lazy", func(b *testing.B) { benchmarkDiscover(b) })
+	b.Run("eager", func(b *testing.B) { benchmarkDiscover(b, WithEagerEvaluation()) })
+}
+
+// benchmarkDiscover discovers a file found by the first of ten providers and reports the number of provider calls
+// and stat calls per discovery.
+func benchmarkDiscover(b *testing.B, options ...Option) {
+	var providerCalls, statCalls int
+
+	providers := make([]Provider, 10)
+	for i := range providers {
+		dir := fmt.Sprintf("dir%v", i)
+		providers[i] = FileLocationsProvider(func(fileName string) ([]string, error) {
+			providerCalls++
+
+			return []string{path.Join(dir, "a", fileName), path.Join(dir, "b", fileName)}, nil
+		})
+	}
+
+	info, err := fs.Stat(fstest.MapFS{"app.yml": &fstest.MapFile{}}, "app.yml")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	statFunc := func(name string) (os.FileInfo, error) {
+		statCalls++
+		if name == "dir0/b/app.yml" {
+			return info, nil
+		}
+
+		return nil, os.ErrNotExist
+	}
+
+	discovery := NewWithProviders(providers, append(options, WithStatFunc(statFunc))...)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := discovery.Discover("app.yml"); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.ReportMetric(float64(providerCalls)/float64(b.N), "providers/op")
+	b.ReportMetric(float64(statCalls)/float64(b.N), "stats/op")
+}
+
+func TestRequired(t *testing.T) {
+
+	fsys := fstest.MapFS{"b/app.yml": &fstest.MapFile{}}
+	fallback := FileLocationProvider(func(fileName string) (string, error) { return "b/" + fileName, nil })
+
+	result, err := NewWithProviders([]Provider{Required(ExplicitPathProvider("")), fallback}, WithFS(fsys)).Discover("app.yml")
+	if err != nil || result != "b/app.yml" {
+		t.Fatalf("expected empty explicit path to be skipped, but got '%s', %v", result, err)
+	}
+
+	_, err = NewWithProviders([]Provider{Required(ExplicitPathProvider("a/app.yml")), fallback}, WithFS(fsys)).Discover("app.yml")
+
+	var notFoundErr *NotFoundError
+	if !errors.As(err, &notFoundErr) || !errors.Is(err, ErrRequired) || !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected discovery to be aborted with ErrRequired, but got: %v", err)
+	}
+
+	if len(notFoundErr.Attempts) != 1 || notFoundErr.Attempts[0].Path != "a/app.yml" {
+		t.Fatalf("expected only the required location to be checked, but got: %+v", notFoundErr.Attempts)
+	}
+
+	provider := Required(Named("config flag", "--config", ExplicitPathProvider("a/app.yml")))
+	if provider.Name() != "config flag" || provider.Describe() != "--config" {
+		t.Fatalf("expected required provider to keep name and description, but got '%s', '%s'", provider.Name(), provider.Describe())
+	}
+
+	_, err = NewWithProviders([]Provider{Named("config flag", "--config", Required(ExplicitPathProvider("a/app.yml"))), fallback}, WithFS(fsys)).Discover("app.yml")
+	if !errors.Is(err, ErrRequired) {
+		t.Fatalf("expected named required provider to abort the discovery, but got: %v", err)
+	}
+}
+
+func TestFileDiscovery_Discover_skipsProvidersReturningErrSkip(t *testing.T) {
+
+	var checked []string
+
+	statFunc := func(name string) (os.FileInfo, error) {
+		checked = append(checked, name)
+
+		return nil, os.ErrNotExist
+	}
+
+	providers := []Provider{
+		Named("failing", "", FileLocationProvider(func(fileName string) (string, error) {
+			return "", errors.New("stub-error")
+		})),
+		Named("skipping", "", FileLocationProvider(func(fileName string) (string, error) {
+			return "", fmt.Errorf("not configured: %w", ErrSkip)
+		})),
+		Named("missing", "", FileLocationProvider(func(fileName string) (string, error) { return "/does-not-exist", nil })),
+	}
+
+	_, err := NewWithProviders(providers, WithStatFunc(statFunc)).Discover("test-file")
+
+	expectedError := "failing: stub-error\n" +
+		"could not find config file at '/does-not-exist' (missing)\n"
+
+	if err == nil || expectedError != err.Error() {
+		t.Fatalf("expected error\n%s\nbut got\n%v", expectedError, err)
+	}
+
+	if !reflect.DeepEqual([]string{"/does-not-exist"}, checked) {
+		t.Fatalf("expected only '/does-not-exist' to be checked, but got %q", checked)
+	}
+}
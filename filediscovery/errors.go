@@ -0,0 +1,155 @@
+package filediscovery
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var (
+	// ErrIsDirectory is reported for a file location that exists, but is a directory.
+	ErrIsDirectory = errors.New("is a directory")
+	// ErrIsNotDirectory is reported for a file location that exists, but is not a directory, if directories are searched.
+	ErrIsNotDirectory = errors.New("is not a directory")
+	// ErrIsSymlink is reported for a file location that is a symbolic link, if symbolic links are not followed.
+	ErrIsSymlink = errors.New("is a symbolic link")
+	// ErrNotMatched is reported for a file location that was rejected by the matcher given with WithMatcher.
+	ErrNotMatched = errors.New("rejected by matcher")
+	// ErrNotWritable is reported for a file location that cannot be written to.
+	ErrNotWritable = errors.New("not writable")
+	// ErrNoMatch is reported for a glob pattern that did not match any file.
+	ErrNoMatch = errors.New("no match")
+	// ErrRequired aborts the discovery, if a file location of a Required provider was rejected.
+	ErrRequired = errors.New("required file location was rejected")
+	// ErrSkip is returned by a provider, which has no file location to offer, for example because it is not configured.
+	// The provider is skipped silently, it is neither reported as error nor is any file location checked.
+	ErrSkip = errors.New("skipped")
+	// ErrUndefinedVariable is returned by a provider, if a directory or sub folder it was given references an undefined
+	// environment variable.
+	ErrUndefinedVariable = errors.New("undefined variable")
+)
+
+type (
+	// NotFoundError is returned by FileDiscoverer if the file could not be found in any of the file locations.
+	// It holds one Attempt per checked location and matches errors.Is / errors.As against the errors of all attempts,
+	// so errors.Is(err, os.ErrPermission) tells whether a location could not be accessed.
+	NotFoundError struct {
+		// FileName is the name of the file that was searched for. Alternative names are separated by comma.
+		FileName string
+		// Attempts lists the checked file locations in sequence of the FileLocationProviders.
+		Attempts []Attempt
+		// Err is set if the discovery was aborted, for example by a cancelled context or a rejected Required location.
+		Err error
+	}
+
+	// Attempt describes a single file location that was checked during discovery.
+	Attempt struct {
+		// Provider is the index of the FileLocationProvider that suggested the location.
+		Provider int
+		// ProviderName is the name of the FileLocationProvider, if it has one.
+		ProviderName string
+		// FileName is the name of the file the location was suggested for.
+		FileName string
+		// Path is the file location suggested by the provider.
+		Path string
+		// ProviderErr is the error returned by the provider, if any. It is reported with the first location of the provider.
+		ProviderErr error
+		// Err is the reason why Path was rejected, for example the error returned by os.Stat.
+		Err error
+	}
+)
+
+// Error lists the provider errors followed by the file locations that were rejected, one per line.
+// If the discovery was aborted, the reason is appended.
+func (e *NotFoundError) Error() string {
+	var sb strings.Builder
+
+	for _, attempt := range e.Attempts {
+		if attempt.ProviderErr != nil {
+			if attempt.ProviderName != "" {
+				sb.WriteString(attempt.ProviderName)
+				sb.WriteString(": ")
+			}
+
+			sb.WriteString(attempt.ProviderErr.Error())
+			sb.WriteString("\n")
+		}
+	}
+
+	for _, attempt := range e.Attempts {
+		if attempt.Err != nil {
+			sb.WriteString(attempt.message())
+			sb.WriteString("\n")
+		}
+	}
+
+	if e.Err != nil {
+		sb.WriteString(fmt.Sprintf("discovery aborted: %v\n", e.Err))
+	}
+
+	return sb.String()
+}
+
+// Is reports whether the discovery was aborted or any of the attempts failed with an error matching target.
+func (e *NotFoundError) Is(target error) bool {
+	if errors.Is(e.Err, target) {
+		return true
+	}
+
+	for _, attempt := range e.Attempts {
+		if errors.Is(attempt.ProviderErr, target) || errors.Is(attempt.Err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first error of the discovery or its attempts that matches target.
+func (e *NotFoundError) As(target interface{}) bool {
+	if e.Err != nil && errors.As(e.Err, target) {
+		return true
+	}
+
+	for _, attempt := range e.Attempts {
+		if attempt.ProviderErr != nil && errors.As(attempt.ProviderErr, target) {
+			return true
+		}
+
+		if attempt.Err != nil && errors.As(attempt.Err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (a Attempt) message() string {
+	if a.ProviderName != "" {
+		return fmt.Sprintf("%s (%s)", a.reason(), a.ProviderName)
+	}
+
+	return a.reason()
+}
+
+func (a Attempt) reason() string {
+	switch {
+	case errors.Is(a.Err, os.ErrNotExist):
+		return fmt.Sprintf("could not find config file at '%s'", a.Path)
+	case errors.Is(a.Err, ErrIsDirectory):
+		return fmt.Sprintf("config file at '%s' is a directory", a.Path)
+	case errors.Is(a.Err, ErrIsNotDirectory):
+		return fmt.Sprintf("config file at '%s' is not a directory", a.Path)
+	case errors.Is(a.Err, ErrIsSymlink):
+		return fmt.Sprintf("config file at '%s' is a symbolic link", a.Path)
+	case errors.Is(a.Err, ErrNotMatched):
+		return fmt.Sprintf("config file at '%s' was rejected by matcher", a.Path)
+	case errors.Is(a.Err, ErrNotWritable):
+		return fmt.Sprintf("config file at '%s' is not writable", a.Path)
+	case errors.Is(a.Err, ErrNoMatch):
+		return fmt.Sprintf("no config file matches '%s'", a.Path)
+	default:
+		return fmt.Sprintf("could not access config file at '%s': %v", a.Path, a.Err)
+	}
+}
@@ -0,0 +1,72 @@
+package filediscovery
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestNotFoundError_Error(t *testing.T) {
+	err := &NotFoundError{
+		FileName: "test-file",
+		Attempts: []Attempt{
+			{Provider: 0, Path: "/a/test-file", Err: &os.PathError{Op: "stat", Path: "/a/test-file", Err: os.ErrNotExist}},
+			{Provider: 1, Path: "", ProviderErr: errors.New("stub-error"), Err: os.ErrNotExist},
+			{Provider: 2, Path: "/c/test-file", Err: ErrIsDirectory},
+			{Provider: 3, Path: "/d/test-file", Err: os.ErrPermission},
+		},
+		Err: context.DeadlineExceeded,
+	}
+
+	expectedMessage := "stub-error\n" +
+		"could not find config file at '/a/test-file'\n" +
+		"could not find config file at ''\n" +
+		"config file at '/c/test-file' is a directory\n" +
+		"could not access config file at '/d/test-file': permission denied\n" +
+		"discovery aborted: context deadline exceeded\n"
+
+	if expectedMessage != err.Error() {
+		t.Fatalf("expected error message\n%s\nbut got\n%s", expectedMessage, err.Error())
+	}
+}
+
+func TestNotFoundError_Is(t *testing.T) {
+	errStub := errors.New("stub-error")
+	err := error(&NotFoundError{
+		Attempts: []Attempt{
+			{Provider: 0, ProviderErr: errStub, Err: os.ErrNotExist},
+			{Provider: 1, Err: &os.PathError{Op: "stat", Path: "/a", Err: os.ErrPermission}},
+		},
+		Err: context.DeadlineExceeded,
+	})
+
+	for _, target := range []error{errStub, os.ErrNotExist, os.ErrPermission, context.DeadlineExceeded} {
+		if !errors.Is(err, target) {
+			t.Fatalf("expected errors.Is(err, %v) to be true", target)
+		}
+	}
+
+	if errors.Is(err, ErrIsDirectory) {
+		t.Fatalf("expected errors.Is(err, %v) to be false", ErrIsDirectory)
+	}
+}
+
+func TestNotFoundError_As(t *testing.T) {
+	pathErr := &os.PathError{Op: "stat", Path: "/a", Err: os.ErrPermission}
+	err := error(&NotFoundError{
+		Attempts: []Attempt{
+			{Provider: 0, Err: os.ErrNotExist},
+			{Provider: 1, Err: pathErr},
+		},
+	})
+
+	var target *os.PathError
+	if !errors.As(err, &target) {
+		t.Fatalf("expected errors.As to find %T", target)
+	}
+
+	if pathErr != target {
+		t.Fatalf("expected errors.As to return %v, but got %v", pathErr, target)
+	}
+}
@@ -0,0 +1,103 @@
+package filediscovery
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+)
+
+var userLookupFunc = user.Lookup
+
+// noExpansionKey marks the context of a discovery, whose providers must not expand the directories given to them.
+type noExpansionKey struct{}
+
+// expansionEnabled returns false, if the discovery ctx belongs to was created with WithExpansion(false).
+func expansionEnabled(ctx context.Context) bool {
+	noExpansion, _ := ctx.Value(noExpansionKey{}).(bool)
+
+	return !noExpansion
+}
+
+// expandPath replaces a leading ~ or ~user by the home directory of the current or the given user, and $VAR or ${VAR}
+// by the value of the environment variable. It is applied to directories given by the user, before any other path
+// is joined to them. If expansion is disabled, filePath is returned as it is.
+func expandPath(ctx context.Context, filePath string) (string, error) {
+	if !expansionEnabled(ctx) {
+		return filePath, nil
+	}
+
+	expandedPath, err := expandTilde(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	return expandEnv(expandedPath)
+}
+
+// expandSubFolders expands $VAR and ${VAR} in every sub folder, if expansion is enabled, and joins them.
+func expandSubFolders(ctx context.Context, subFolders ...string) (string, error) {
+	if !expansionEnabled(ctx) {
+		return createPath(subFolders...), nil
+	}
+
+	expandedSubFolders := make([]string, len(subFolders))
+	for i, subFolder := range subFolders {
+		expandedSubFolder, err := expandEnv(subFolder)
+		if err != nil {
+			return "", err
+		}
+
+		expandedSubFolders[i] = expandedSubFolder
+	}
+
+	return createPath(expandedSubFolders...), nil
+}
+
+func expandTilde(filePath string) (string, error) {
+	if !strings.HasPrefix(filePath, "~") {
+		return filePath, nil
+	}
+
+	userName, rest := filePath[1:], ""
+	if i := strings.IndexAny(userName, "/"+string(os.PathSeparator)); i >= 0 {
+		userName, rest = userName[:i], userName[i:]
+	}
+
+	lookup := homeFolderLookupFunc
+	if userName != "" {
+		lookup = func() (*user.User, error) { return userLookupFunc(userName) }
+	}
+
+	usr, err := lookup()
+	if err != nil {
+		return "", fmt.Errorf("could not expand '%s': %w", filePath, err)
+	}
+
+	return usr.HomeDir + rest, nil
+}
+
+// expandEnv replaces $VAR and ${VAR} by the value of the environment variable, and $$ by a literal $.
+func expandEnv(filePath string) (string, error) {
+	var undefined []string
+
+	expandedPath := os.Expand(filePath, func(name string) string {
+		if name == "$" {
+			return name
+		}
+
+		value, ok := envLookupFunc(name)
+		if !ok {
+			undefined = append(undefined, name)
+		}
+
+		return value
+	})
+
+	if len(undefined) > 0 {
+		return "", fmt.Errorf("could not expand '%s': %w '%s'", filePath, ErrUndefinedVariable, undefined[0])
+	}
+
+	return expandedPath, nil
+}
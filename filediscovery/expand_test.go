@@ -0,0 +1,294 @@
+package filediscovery
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/user"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func stubExpansion(t *testing.T) {
+	t.Helper()
+
+	stubEnv(t, map[string]string{
+		"APP":        "app",
+		"CONFIG_DIR": "/srv/config",
+		"APP_CONFIG": "~/configs/app.yml",
+		"APP_HOME":   "$CONFIG_DIR/app",
+		"APP_PATH":   "~/a" + string(os.PathListSeparator) + "${CONFIG_DIR}",
+		"BROKEN":     "$UNDEFINED/app",
+	})
+
+	originalHomeFolderLookupFunc := homeFolderLookupFunc
+	originalUserLookupFunc := userLookupFunc
+
+	homeFolderLookupFunc = func() (*user.User, error) { return &user.User{HomeDir: "/home/me"}, nil }
+	userLookupFunc = func(name string) (*user.User, error) {
+		if name != "other" {
+			return nil, user.UnknownUserError(name)
+		}
+
+		return &user.User{HomeDir: "/home/other"}, nil
+	}
+
+	t.Cleanup(func() {
+		homeFolderLookupFunc = originalHomeFolderLookupFunc
+		userLookupFunc = originalUserLookupFunc
+	})
+}
+
+func TestExpandPath(t *testing.T) {
+	stubExpansion(t)
+
+	testDataSet := map[string]struct {
+		Path         string
+		ExpectedPath string
+	}{
+		"plain path":         {Path: "/etc/app.yml", ExpectedPath: "/etc/app.yml"},
+		"tilde":              {Path: "~/configs/app.yml", ExpectedPath: "/home/me/configs/app.yml"},
+		"tilde only":         {Path: "~", ExpectedPath: "/home/me"},
+		"tilde user":         {Path: "~other/app.yml", ExpectedPath: "/home/other/app.yml"},
+		"tilde not leading":  {Path: "/etc/~/app.yml", ExpectedPath: "/etc/~/app.yml"},
+		"variable":           {Path: "$CONFIG_DIR/app.yml", ExpectedPath: "/srv/config/app.yml"},
+		"braced variable":    {Path: "/etc/${APP}/app.yml", ExpectedPath: "/etc/app/app.yml"},
+		"tilde and variable": {Path: "~/$APP/app.yml", ExpectedPath: "/home/me/app/app.yml"},
+		"escaped dollar":     {Path: "/etc/$$APP/app.yml", ExpectedPath: "/etc/$APP/app.yml"},
+	}
+
+	for testName, testData := range testDataSet {
+		t.Run(testName, func(t *testing.T) {
+			result, err := expandPath(context.Background(), testData.Path)
+			if err != nil {
+				t.Fatalf("Did not expect expandPath to return an error, but got: %v", err)
+			}
+
+			if result != testData.ExpectedPath {
+				t.Fatalf("expected '%s' to be expanded to '%s', but got '%s'", testData.Path, testData.ExpectedPath, result)
+			}
+		})
+	}
+}
+
+func TestExpandPath_errors(t *testing.T) {
+	stubExpansion(t)
+
+	_, err := expandPath(context.Background(), "$UNDEFINED/app.yml")
+	if !errors.Is(err, ErrUndefinedVariable) || err.Error() != "could not expand '$UNDEFINED/app.yml': undefined variable 'UNDEFINED'" {
+		t.Fatalf("expected undefined variable error, but got: %v", err)
+	}
+
+	var unknownUserErr user.UnknownUserError
+	if _, err := expandPath(context.Background(), "~unknown/app.yml"); !errors.As(err, &unknownUserErr) {
+		t.Fatalf("expected unknown user error, but got: %v", err)
+	}
+}
+
+func TestProviders_expandGivenDirectories(t *testing.T) {
+	stubExpansion(t)
+
+	testDataSet := map[string]struct {
+		Provider      Provider
+		ExpectedPaths []string
+	}{
+		"sub folders": {
+			Provider:      NamedHomeConfigDirProvider(".config", "$APP"),
+			ExpectedPaths: []string{"/home/me/.config/app/app.yml"},
+		},
+		"system config dir prefixes": {
+			Provider:      SystemConfigDirPrefixesProvider([]string{"$CONFIG_DIR/etc", "~/etc"}),
+			ExpectedPaths: []string{"/srv/config/etc/app.yml", "/home/me/etc/app.yml"},
+		},
+		"parent dirs start and ceiling dir": {
+			Provider:      ParentDirsSearchProvider(ParentDirsSearch{StartDir: "~/proj", CeilingDir: "~"}),
+			ExpectedPaths: []string{"/home/me/proj/app.yml", "/home/me/app.yml"},
+		},
+		"env var file path": {
+			Provider:      NamedEnvVarFilePathProvider("APP_CONFIG"),
+			ExpectedPaths: []string{"/home/me/configs/app.yml"},
+		},
+		"env var dir": {
+			Provider:      EnvVarDirProvider("APP_HOME", "conf"),
+			ExpectedPaths: []string{"/srv/config/app/conf/app.yml"},
+		},
+		"path list": {
+			Provider:      PathListProvider(PathList{EnvVar: "APP_PATH"}),
+			ExpectedPaths: []string{"/home/me/a/app.yml", "/srv/config/app.yml"},
+		},
+	}
+
+	for testName, testData := range testDataSet {
+		t.Run(testName, func(t *testing.T) {
+			result, err := testData.Provider.Locate("app.yml")
+			if err != nil {
+				t.Fatalf("Did not expect provider to return an error, but got: %v", err)
+			}
+
+			if !reflect.DeepEqual(testData.ExpectedPaths, result) {
+				t.Fatalf("expected provider to return %v, but got %v", testData.ExpectedPaths, result)
+			}
+		})
+	}
+}
+
+func TestProviders_doNotExpandResolvedDirectories(t *testing.T) {
+	stubExpansion(t)
+
+	originalWorkingDirProviderFunc := workingDirProviderFunc
+	originalExecutableDirProviderFunc := executableDirProviderFunc
+	originalHomeFolderLookupFunc := homeFolderLookupFunc
+
+	workingDirProviderFunc = func() (string, error) { return "/tmp/x/build$1", nil }
+	executableDirProviderFunc = func() (string, error) { return "/opt/a$HOMEX/app", nil }
+	homeFolderLookupFunc = func() (*user.User, error) { return &user.User{HomeDir: "/home/~me$"}, nil }
+
+	t.Cleanup(func() {
+		workingDirProviderFunc = originalWorkingDirProviderFunc
+		executableDirProviderFunc = originalExecutableDirProviderFunc
+		homeFolderLookupFunc = originalHomeFolderLookupFunc
+	})
+
+	testDataSet := map[string]struct {
+		Provider      Provider
+		ExpectedPaths []string
+	}{
+		"working dir": {
+			Provider:      NamedWorkingDirProvider("$APP"),
+			ExpectedPaths: []string{"/tmp/x/build$1/app/app.yml"},
+		},
+		"executable dir": {
+			Provider:      NamedExecutableDirProvider(),
+			ExpectedPaths: []string{"/opt/a$HOMEX/app.yml"},
+		},
+		"home config dir": {
+			Provider:      NamedHomeConfigDirProvider(),
+			ExpectedPaths: []string{"/home/~me$/app.yml"},
+		},
+		"relative system config dir prefix": {
+			Provider:      SystemConfigDirPrefixesProvider([]string{"../etc"}),
+			ExpectedPaths: []string{"/opt/etc/app.yml"},
+		},
+		"parent dirs": {
+			Provider:      ParentDirsSearchProvider(ParentDirsSearch{CeilingDir: "/tmp/x"}),
+			ExpectedPaths: []string{"/tmp/x/build$1/app.yml", "/tmp/x/app.yml"},
+		},
+	}
+
+	for testName, testData := range testDataSet {
+		t.Run(testName, func(t *testing.T) {
+			result, err := testData.Provider.Locate("app.yml")
+			if err != nil {
+				t.Fatalf("Did not expect provider to return an error, but got: %v", err)
+			}
+
+			if !reflect.DeepEqual(testData.ExpectedPaths, result) {
+				t.Fatalf("expected provider to return %v, but got %v", testData.ExpectedPaths, result)
+			}
+		})
+	}
+}
+
+func TestProviders_plainFileLocationProvidersDoNotExpand(t *testing.T) {
+	stubExpansion(t)
+
+	originalWorkingDirProviderFunc := workingDirProviderFunc
+	workingDirProviderFunc = func() (string, error) { return "/tmp/x", nil }
+	t.Cleanup(func() { workingDirProviderFunc = originalWorkingDirProviderFunc })
+
+	testDataSet := map[string]struct {
+		Provider     FileLocationProvider
+		ExpectedPath string
+	}{
+		"working dir": {
+			Provider:     WorkingDirProvider("$APP"),
+			ExpectedPath: "/tmp/x/$APP/app.yml",
+		},
+		"home config dir": {
+			Provider:     HomeConfigDirProvider("~", "${APP}"),
+			ExpectedPath: "/home/me/~/${APP}/app.yml",
+		},
+		"env var file path": {
+			Provider:     EnvVarFilePathProvider("APP_CONFIG"),
+			ExpectedPath: "~/configs/app.yml",
+		},
+	}
+
+	for testName, testData := range testDataSet {
+		t.Run(testName, func(t *testing.T) {
+			result, err := testData.Provider("app.yml")
+			if err != nil {
+				t.Fatalf("Did not expect provider to return an error, but got: %v", err)
+			}
+
+			if result != testData.ExpectedPath {
+				t.Fatalf("expected provider to return '%s', but got '%s'", testData.ExpectedPath, result)
+			}
+		})
+	}
+}
+
+func TestWithExpansion(t *testing.T) {
+	dirs := createTestDirs(t, 1)
+	defer removeTestDirs(t, dirs)
+
+	literalDir := filepath.Join(dirs[0], "d$x")
+	if err := os.Mkdir(literalDir, 0700); err != nil {
+		t.Fatalf("did not expect os.Mkdir to return an error, but got: %v", err)
+	}
+
+	testFilePath := filepath.Join(literalDir, "app.yml")
+	createTestFile(t, testFilePath)
+
+	stubEnv(t, map[string]string{"APPCFG": testFilePath, "APPDIR": literalDir})
+
+	providers := []Provider{NamedEnvVarFilePathProvider("APPCFG"), EnvVarDirProvider("APPDIR")}
+
+	filePaths, err := NewWithProviders(providers, WithExpansion(false)).DiscoverAll("app.yml")
+	if err != nil {
+		t.Fatalf("did not expect discovery.DiscoverAll to return an error, but got: %v", err)
+	}
+
+	if !reflect.DeepEqual([]string{testFilePath}, filePaths) {
+		t.Fatalf("expected discovery.DiscoverAll to return [%s], but got %v", testFilePath, filePaths)
+	}
+
+	_, err = NewWithProviders(providers).Discover("app.yml")
+	if !errors.Is(err, ErrUndefinedVariable) {
+		t.Fatalf("expected expansion to be enabled by default, but got: %v", err)
+	}
+
+	filePath, err := New([]FileLocationProvider{EnvVarFilePathProvider("APPCFG")}).Discover("app.yml")
+	if err != nil || filePath != testFilePath {
+		t.Fatalf("expected EnvVarFilePathProvider to return '%s' as it is, but got '%s', %v", testFilePath, filePath, err)
+	}
+}
+
+func TestFileDiscovery_Discover_reportsUndefinedVariables(t *testing.T) {
+	stubExpansion(t)
+
+	statCalled := false
+	statFunc := func(name string) (os.FileInfo, error) {
+		statCalled = true
+
+		return nil, os.ErrNotExist
+	}
+
+	providers := []Provider{EnvVarDirProvider("BROKEN")}
+
+	_, err := NewWithProviders(providers, WithStatFunc(statFunc)).Discover("app.yml")
+
+	expectedError := "env var BROKEN: could not expand '$UNDEFINED/app': undefined variable 'UNDEFINED'\n"
+	if err == nil || expectedError != err.Error() {
+		t.Fatalf("expected error\n%s\nbut got\n%v", expectedError, err)
+	}
+
+	if !errors.Is(err, ErrUndefinedVariable) {
+		t.Fatalf("expected error to match ErrUndefinedVariable, but got: %v", err)
+	}
+
+	if statCalled {
+		t.Fatalf("expected no file location to be checked")
+	}
+}
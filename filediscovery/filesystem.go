@@ -0,0 +1,128 @@
+package filediscovery
+
+import (
+	"io/fs"
+	"os"
+)
+
+// fileSystem is the file system FileDiscovery checks the file locations against.
+type fileSystem interface {
+	Stat(name string) (fs.FileInfo, error)
+	Lstat(name string) (fs.FileInfo, error)
+	ReadDir(name string) ([]fs.DirEntry, error)
+	Open(name string) (fs.File, error)
+	MkdirAll(name string, perm fs.FileMode) error
+	CheckWritable(name string) error
+}
+
+type osFileSystem struct{}
+
+func (osFileSystem) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (osFileSystem) Lstat(name string) (fs.FileInfo, error) {
+	return os.Lstat(name)
+}
+
+func (osFileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.ReadDir(name)
+}
+
+func (osFileSystem) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
+
+func (osFileSystem) MkdirAll(name string, perm fs.FileMode) error {
+	return os.MkdirAll(name, perm)
+}
+
+// CheckWritable opens the file of the given name for writing, without changing it. If name is a directory, it creates
+// and removes a temporary file in it, which is the only portable way to check write access to a directory.
+func (osFileSystem) CheckWritable(name string) error {
+	info, err := os.Stat(name)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		f, err := os.OpenFile(name, os.O_WRONLY, 0)
+		if err != nil {
+			return err
+		}
+
+		return f.Close()
+	}
+
+	f, err := os.CreateTemp(name, ".filediscovery-*")
+	if err != nil {
+		return err
+	}
+
+	_ = f.Close()
+
+	return os.Remove(f.Name())
+}
+
+type ioFileSystem struct {
+	fsys fs.FS
+}
+
+func (f ioFileSystem) Stat(name string) (fs.FileInfo, error) {
+	return fs.Stat(f.fsys, name)
+}
+
+// Lstat uses the Lstat method of the underlying fs.FS if available, otherwise it falls back to Stat.
+func (f ioFileSystem) Lstat(name string) (fs.FileInfo, error) {
+	if lstatFS, ok := f.fsys.(interface {
+		Lstat(name string) (fs.FileInfo, error)
+	}); ok {
+		return lstatFS.Lstat(name)
+	}
+
+	return f.Stat(name)
+}
+
+func (f ioFileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(f.fsys, name)
+}
+
+func (f ioFileSystem) Open(name string) (fs.File, error) {
+	return f.fsys.Open(name)
+}
+
+func (f ioFileSystem) MkdirAll(name string, _ fs.FileMode) error {
+	return &fs.PathError{Op: "mkdir", Path: name, Err: ErrNotWritable}
+}
+
+func (f ioFileSystem) CheckWritable(name string) error {
+	return &fs.PathError{Op: "write", Path: name, Err: ErrNotWritable}
+}
+
+type statFuncFileSystem func(name string) (os.FileInfo, error)
+
+func (f statFuncFileSystem) Stat(name string) (fs.FileInfo, error) {
+	return f(name)
+}
+
+func (f statFuncFileSystem) Lstat(name string) (fs.FileInfo, error) {
+	return f(name)
+}
+
+// ReadDir reads from the file system of the operating system, since a stat function cannot list directories.
+func (f statFuncFileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.ReadDir(name)
+}
+
+// Open opens from the file system of the operating system, since a stat function cannot open files.
+func (f statFuncFileSystem) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
+
+func (f statFuncFileSystem) MkdirAll(name string, perm fs.FileMode) error {
+	return osFileSystem{}.MkdirAll(name, perm)
+}
+
+func (f statFuncFileSystem) CheckWritable(name string) error {
+	return osFileSystem{}.CheckWritable(name)
+}
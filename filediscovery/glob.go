@@ -0,0 +1,185 @@
+package filediscovery
+
+import (
+	"context"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const recursiveSegment = "**"
+
+// DiscoverGlob evaluates the glob pattern relative to the file locations of all FileLocationProviders and returns
+// the sorted matches of the first location that has any. Besides the syntax of path.Match, a "**" segment
+// matches any number of directories. Directories that cannot be read below a location are skipped.
+// Only the pattern is evaluated, the directory a provider located it in is taken literally. A location which does not
+// end with the pattern, like the one of ExplicitPathProvider, is checked as it is.
+// A "**" segment walks the whole tree below a location, so combined with ParentDirsProvider, which ends at the root
+// directory, it may walk the whole file system if a location has no match.
+// If no location has a match a *NotFoundError is returned, which lists the result of every location.
+func (fd *FileDiscovery) DiscoverGlob(pattern string) ([]string, error) {
+	for _, segment := range strings.Split(pattern, "/") {
+		if _, err := path.Match(segment, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx := context.Background()
+	start := time.Now()
+
+	var matches []string
+
+	attempts, found, abortErr := fd.search(ctx, fd.providerBatches(ctx, pattern), func(attempt *Attempt) bool {
+		checkStart := time.Now()
+		matches, attempt.Err = fd.glob(ctx, attempt.Path, pattern)
+		fd.observeCheck(attempt, nil, checkStart)
+
+		return attempt.Err == nil
+	})
+
+	if found < 0 {
+		err := &NotFoundError{FileName: pattern, Attempts: attempts, Err: abortErr}
+		fd.observeDecision(pattern, nil, err, start)
+
+		return nil, err
+	}
+
+	fd.observeDecision(pattern, matches, nil, start)
+
+	return matches, nil
+}
+
+// glob evaluates the pattern in the directory the provider located it in. filePath is the location returned by the
+// provider.
+func (fd *FileDiscovery) glob(ctx context.Context, filePath string, pattern string) ([]string, error) {
+	base, rel, ok := splitGlobBase(filePath, pattern)
+	if !ok {
+		return fd.globLiteral(ctx, filePath)
+	}
+
+	segments := strings.Split(rel, "/")
+
+	first := 0
+	for first < len(segments) && !hasMeta(segments[first]) {
+		first++
+	}
+
+	if first == len(segments) {
+		return fd.globLiteral(ctx, filePath)
+	}
+
+	dir := base
+	if first > 0 {
+		dir = path.Join(append([]string{base}, segments[:first]...)...)
+	}
+
+	if _, err := fd.fileSystem.ReadDir(filepath.FromSlash(dir)); err != nil {
+		return nil, err
+	}
+
+	var matches []string
+
+	fd.globSegments(ctx, dir, segments[first:], &matches)
+
+	if len(matches) == 0 {
+		return nil, ErrNoMatch
+	}
+
+	sort.Strings(matches)
+
+	return unique(matches), nil
+}
+
+func (fd *FileDiscovery) globLiteral(ctx context.Context, filePath string) ([]string, error) {
+	if _, err := fd.check(ctx, filePath); err != nil {
+		return nil, err
+	}
+
+	return []string{filePath}, nil
+}
+
+// splitGlobBase splits the location returned by a provider into the slash separated directory the provider located the
+// pattern in and the cleaned pattern relative to it. ok is false if filePath does not end with the pattern.
+// Leading ".." segments of the pattern are dropped, since the provider resolved them when joining the pattern.
+func splitGlobBase(filePath string, pattern string) (base string, rel string, ok bool) {
+	slashPath := filepath.ToSlash(filePath)
+
+	rel = strings.TrimPrefix(path.Clean(pattern), "/")
+	for strings.HasPrefix(rel, "../") {
+		rel = strings.TrimPrefix(rel, "../")
+	}
+
+	switch {
+	case slashPath == rel:
+		return ".", rel, true
+	case strings.HasSuffix(slashPath, "/"+rel):
+		base = strings.TrimSuffix(slashPath, "/"+rel)
+		if base == "" || base == filepath.ToSlash(filepath.VolumeName(filePath)) {
+			base += "/"
+		}
+
+		return base, rel, true
+	}
+
+	return "", "", false
+}
+
+func (fd *FileDiscovery) globSegments(ctx context.Context, dir string, segments []string, matches *[]string) {
+	if len(segments) == 0 {
+		if _, err := fd.check(ctx, filepath.FromSlash(dir)); err == nil {
+			*matches = append(*matches, filepath.FromSlash(dir))
+		}
+
+		return
+	}
+
+	segment := segments[0]
+
+	if !hasMeta(segment) {
+		fd.globSegments(ctx, path.Join(dir, segment), segments[1:], matches)
+
+		return
+	}
+
+	if segment == recursiveSegment {
+		fd.globSegments(ctx, dir, segments[1:], matches)
+	}
+
+	entries, err := fd.fileSystem.ReadDir(filepath.FromSlash(dir))
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		entryPath := path.Join(dir, entry.Name())
+
+		if segment == recursiveSegment {
+			if entry.IsDir() {
+				fd.globSegments(ctx, entryPath, segments, matches)
+			}
+
+			continue
+		}
+
+		if matched, _ := path.Match(segment, entry.Name()); matched {
+			fd.globSegments(ctx, entryPath, segments[1:], matches)
+		}
+	}
+}
+
+func hasMeta(segment string) bool {
+	return strings.ContainsAny(segment, `*?[\`)
+}
+
+func unique(sorted []string) []string {
+	result := sorted[:0]
+	for i, s := range sorted {
+		if i == 0 || s != sorted[i-1] {
+			result = append(result, s)
+		}
+	}
+
+	return result
+}
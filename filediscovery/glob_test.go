@@ -0,0 +1,211 @@
+package filediscovery
+
+import (
+	"errors"
+	"path"
+	"reflect"
+	"runtime"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFileDiscovery_DiscoverGlob(t *testing.T) {
+
+	fsys := fstest.MapFS{
+		"user/certs/b.pem":               &fstest.MapFile{},
+		"user/certs/a.pem":               &fstest.MapFile{},
+		"user/certs/a.key":               &fstest.MapFile{},
+		"system/certs/c.pem":             &fstest.MapFile{},
+		"user/conf.d/app.yaml":           &fstest.MapFile{},
+		"user/conf.d/nested/db.yaml":     &fstest.MapFile{},
+		"user/conf.d/nested/deep/x.yaml": &fstest.MapFile{},
+		"user/conf.d/dir.yaml/file":      &fstest.MapFile{},
+	}
+
+	providers := []Provider{
+		FileLocationsProvider(func(fileName string) ([]string, error) {
+			return []string{path.Join("project", fileName), path.Join("user", fileName), path.Join("system", fileName)}, nil
+		}),
+	}
+
+	testDataSet := map[string]struct {
+		Pattern         string
+		ExpectedMatches []string
+	}{
+		"first location with matches": {
+			Pattern:         "certs/*.pem",
+			ExpectedMatches: []string{"user/certs/a.pem", "user/certs/b.pem"},
+		},
+		"directories do not match": {
+			Pattern:         "conf.d/*.yaml",
+			ExpectedMatches: []string{"user/conf.d/app.yaml"},
+		},
+		"recursive segment": {
+			Pattern: "conf.d/**/*.yaml",
+			ExpectedMatches: []string{
+				"user/conf.d/app.yaml",
+				"user/conf.d/nested/db.yaml",
+				"user/conf.d/nested/deep/x.yaml",
+			},
+		},
+		"recursive segment followed by static segment": {
+			Pattern:         "**/deep/x.yaml",
+			ExpectedMatches: []string{"user/conf.d/nested/deep/x.yaml"},
+		},
+		"without meta characters": {
+			Pattern:         "certs/c.pem",
+			ExpectedMatches: []string{"system/certs/c.pem"},
+		},
+	}
+
+	for testCaseName, testData := range testDataSet {
+		t.Run(testCaseName, func(t *testing.T) {
+			matches, err := NewWithProviders(providers, WithFS(fsys)).DiscoverGlob(testData.Pattern)
+			if err != nil {
+				t.Fatalf("did not expect discovery.DiscoverGlob to return an error, but got: %v", err)
+			}
+
+			if !reflect.DeepEqual(testData.ExpectedMatches, matches) {
+				t.Fatalf("expected matches %v, but got %v", testData.ExpectedMatches, matches)
+			}
+		})
+	}
+}
+
+func TestFileDiscovery_DiscoverGlob_ifNothingMatchesReturnsNotFoundError(t *testing.T) {
+
+	fsys := fstest.MapFS{
+		"user/certs/a.key": &fstest.MapFile{},
+	}
+
+	providers := []Provider{
+		FileLocationsProvider(func(fileName string) ([]string, error) {
+			return []string{path.Join("project", fileName), path.Join("user", fileName)}, nil
+		}),
+	}
+
+	_, err := NewWithProviders(providers, WithFS(fsys)).DiscoverGlob("certs/*.pem")
+
+	var notFoundErr *NotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Fatalf("expected error to be a %T, but got: %v", notFoundErr, err)
+	}
+
+	expectedError := "could not find config file at 'project/certs/*.pem'\n" +
+		"no config file matches 'user/certs/*.pem'\n"
+
+	if expectedError != err.Error() {
+		t.Fatalf("expected error\n%s\nbut got\n%s", expectedError, err.Error())
+	}
+}
+
+func TestFileDiscovery_DiscoverGlob_invalidPattern(t *testing.T) {
+
+	_, provider := newFileLocationProviderMock()
+
+	_, err := NewWithProviders([]Provider{provider}).DiscoverGlob("[")
+	if !errors.Is(err, path.ErrBadPattern) {
+		t.Fatalf("expected error %v, but got: %v", path.ErrBadPattern, err)
+	}
+}
+
+func TestFileDiscovery_DiscoverGlob_takesLocationDirectoryLiterally(t *testing.T) {
+
+	fsys := fstest.MapFS{
+		"user/[certs]*/a.pem": &fstest.MapFile{},
+		"user/c/a.pem":        &fstest.MapFile{},
+		"explicit/app.pem":    &fstest.MapFile{},
+	}
+
+	testDataSet := map[string]struct {
+		Location        string
+		Pattern         string
+		ExpectedMatches []string
+	}{
+		"meta characters in directory": {
+			Location:        "user/[certs]*/*.pem",
+			Pattern:         "*.pem",
+			ExpectedMatches: []string{"user/[certs]*/a.pem"},
+		},
+		"location not ending with pattern": {
+			Location:        "explicit/app.pem",
+			Pattern:         "*.pem",
+			ExpectedMatches: []string{"explicit/app.pem"},
+		},
+	}
+
+	for testCaseName, testData := range testDataSet {
+		t.Run(testCaseName, func(t *testing.T) {
+			location := testData.Location
+			providers := []Provider{
+				FileLocationsProvider(func(fileName string) ([]string, error) {
+					return []string{location}, nil
+				}),
+			}
+
+			matches, err := NewWithProviders(providers, WithFS(fsys)).DiscoverGlob(testData.Pattern)
+			if err != nil {
+				t.Fatalf("did not expect discovery.DiscoverGlob to return an error, but got: %v", err)
+			}
+
+			if !reflect.DeepEqual(testData.ExpectedMatches, matches) {
+				t.Fatalf("expected matches %v, but got %v", testData.ExpectedMatches, matches)
+			}
+		})
+	}
+}
+
+func TestSplitGlobBase(t *testing.T) {
+
+	type testCase struct {
+		FilePath     string
+		Pattern      string
+		ExpectedBase string
+		ExpectedRel  string
+		ExpectedOK   bool
+	}
+
+	testDataSet := map[string]testCase{
+		"relative location": {
+			FilePath: "user/certs/*.pem", Pattern: "certs/*.pem",
+			ExpectedBase: "user", ExpectedRel: "certs/*.pem", ExpectedOK: true,
+		},
+		"location equals pattern": {
+			FilePath: "certs/*.pem", Pattern: "certs/*.pem",
+			ExpectedBase: ".", ExpectedRel: "certs/*.pem", ExpectedOK: true,
+		},
+		"root directory": {
+			FilePath: "/*.pem", Pattern: "*.pem",
+			ExpectedBase: "/", ExpectedRel: "*.pem", ExpectedOK: true,
+		},
+		"pattern leaving the directory": {
+			FilePath: "/home/x/*.pem", Pattern: "../x/*.pem",
+			ExpectedBase: "/home", ExpectedRel: "x/*.pem", ExpectedOK: true,
+		},
+		"location not ending with pattern": {
+			FilePath: "/etc/app.pem", Pattern: "*.key",
+			ExpectedOK: false,
+		},
+	}
+
+	if runtime.GOOS == "windows" {
+		testDataSet["windows path"] = testCase{
+			FilePath: `C:\Users\x\certs\*.pem`, Pattern: "certs/*.pem",
+			ExpectedBase: "C:/Users/x", ExpectedRel: "certs/*.pem", ExpectedOK: true,
+		}
+		testDataSet["windows volume"] = testCase{
+			FilePath: `C:\*.pem`, Pattern: "*.pem",
+			ExpectedBase: "C:/", ExpectedRel: "*.pem", ExpectedOK: true,
+		}
+	}
+
+	for testCaseName, testData := range testDataSet {
+		t.Run(testCaseName, func(t *testing.T) {
+			base, rel, ok := splitGlobBase(testData.FilePath, testData.Pattern)
+			if testData.ExpectedOK != ok || testData.ExpectedBase != base || testData.ExpectedRel != rel {
+				t.Fatalf("expected (%q, %q, %v), but got (%q, %q, %v)",
+					testData.ExpectedBase, testData.ExpectedRel, testData.ExpectedOK, base, rel, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package filediscovery
+
+import (
+	"context"
+	"strings"
+)
+
+// Order defines the sequence in which alternative file names are searched in the file locations.
+type Order int
+
+const (
+	// LocationFirst checks all file names in a location before checking the next location.
+	LocationFirst Order = iota
+	// NameFirst checks a file name in all locations before checking the next file name.
+	NameFirst
+)
+
+// FileNames returns the given baseName combined with each of the given extensions, for example
+// FileNames("config", "yaml", "json") returns config.yaml and config.json.
+func FileNames(baseName string, extensions ...string) []string {
+	fileNames := make([]string, len(extensions))
+	for i, extension := range extensions {
+		fileNames[i] = baseName + "." + strings.TrimPrefix(extension, ".")
+	}
+
+	return fileNames
+}
+
+// DiscoverAny tries to find any of the given alternative fileNames in all FileLocationProviders. The order defines
+// whether all names are checked in a location before the next location is checked, or the other way around.
+// The path and the name of the first matching file are returned.
+func (fd *FileDiscovery) DiscoverAny(fileNames []string, order Order) (string, string, error) {
+	ctx := context.Background()
+
+	result, err := fd.discoverFirst(ctx, strings.Join(fileNames, ", "), fd.nameBatches(ctx, fileNames, order))
+	if err != nil {
+		return "", "", err
+	}
+
+	return result.Path, result.FileName, nil
+}
+
+func (fd *FileDiscovery) nameBatches(ctx context.Context, fileNames []string, order Order) []attemptBatch {
+	var batches []attemptBatch
+
+	if order == NameFirst {
+		for _, fileName := range fileNames {
+			batches = append(batches, fd.providerBatches(ctx, fileName)...)
+		}
+
+		return batches
+	}
+
+	for i := range fd.fileLocationProviders {
+		provider := i
+		batches = append(batches, func() []Attempt {
+			attemptsPerName := make([][]Attempt, len(fileNames))
+			for j, fileName := range fileNames {
+				attemptsPerName[j] = fd.collectProviderAttempts(ctx, provider, fileName)
+			}
+
+			return interleave(attemptsPerName)
+		})
+	}
+
+	return batches
+}
+
+// interleave combines the attempts of all names for the same location, so a location is checked with every name
+// before the next location of the provider is checked.
+func interleave(attemptsPerName [][]Attempt) []Attempt {
+	var attempts []Attempt
+
+	for i := 0; ; i++ {
+		added := false
+
+		for _, nameAttempts := range attemptsPerName {
+			if i < len(nameAttempts) {
+				attempts = append(attempts, nameAttempts[i])
+				added = true
+			}
+		}
+
+		if !added {
+			return attempts
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package filediscovery
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFileNames(t *testing.T) {
+	result := FileNames("config", "yaml", ".yml", "json")
+
+	expected := []string{"config.yaml", "config.yml", "config.json"}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("expected FileNames to return %v, but got %v", expected, result)
+	}
+}
+
+func TestFileDiscovery_DiscoverAny(t *testing.T) {
+
+	fsys := fstest.MapFS{
+		"project/config.json": &fstest.MapFile{},
+		"user/config.yaml":    &fstest.MapFile{},
+		"system/config.yml":   &fstest.MapFile{},
+	}
+
+	providers := []Provider{
+		FileLocationProvider(func(fileName string) (string, error) { return "project/" + fileName, nil }),
+		FileLocationsProvider(func(fileName string) ([]string, error) {
+			return []string{"system/" + fileName, "user/" + fileName}, nil
+		}),
+	}
+
+	testDataSet := map[string]struct {
+		Order            Order
+		FileNames        []string
+		ExpectedPath     string
+		ExpectedFileName string
+	}{
+		"location first": {
+			Order:            LocationFirst,
+			FileNames:        FileNames("config", "yaml", "yml", "json"),
+			ExpectedPath:     "project/config.json",
+			ExpectedFileName: "config.json",
+		},
+		"location first within multiple locations of a provider": {
+			Order:            LocationFirst,
+			FileNames:        FileNames("config", "yaml", "yml"),
+			ExpectedPath:     "system/config.yml",
+			ExpectedFileName: "config.yml",
+		},
+		"name first": {
+			Order:            NameFirst,
+			FileNames:        FileNames("config", "yaml", "yml", "json"),
+			ExpectedPath:     "user/config.yaml",
+			ExpectedFileName: "config.yaml",
+		},
+	}
+
+	for testCaseName, testData := range testDataSet {
+		t.Run(testCaseName, func(t *testing.T) {
+			discovery := NewWithProviders(providers, WithFS(fsys))
+
+			filePath, fileName, err := discovery.DiscoverAny(testData.FileNames, testData.Order)
+			if err != nil {
+				t.Fatalf("did not expect discovery.DiscoverAny to return an error, but got: %v", err)
+			}
+
+			if testData.ExpectedPath != filePath {
+				t.Fatalf("expected path '%s', but got '%s'", testData.ExpectedPath, filePath)
+			}
+
+			if testData.ExpectedFileName != fileName {
+				t.Fatalf("expected file name '%s', but got '%s'", testData.ExpectedFileName, fileName)
+			}
+		})
+	}
+}
+
+func TestFileDiscovery_DiscoverAny_ifFileNotFoundReturnsNotFoundError(t *testing.T) {
+
+	providers := []Provider{
+		FileLocationProvider(func(fileName string) (string, error) { return "a/" + fileName, nil }),
+		FileLocationProvider(func(fileName string) (string, error) { return "b/" + fileName, nil }),
+	}
+
+	discovery := NewWithProviders(providers, WithFS(fstest.MapFS{}))
+	_, _, err := discovery.DiscoverAny([]string{"x", "y"}, LocationFirst)
+
+	var notFoundErr *NotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Fatalf("expected error to be a %T, but got: %v", notFoundErr, err)
+	}
+
+	var checked []string
+	for _, attempt := range notFoundErr.Attempts {
+		checked = append(checked, attempt.Path)
+	}
+
+	expected := []string{"a/x", "a/y", "b/x", "b/y"}
+	if !reflect.DeepEqual(expected, checked) {
+		t.Fatalf("expected locations %v to be checked, but got %v", expected, checked)
+	}
+
+	if notFoundErr.FileName != "x, y" {
+		t.Fatalf("expected error to name 'x, y', but got '%s'", notFoundErr.FileName)
+	}
+}
@@ -0,0 +1,143 @@
+package filediscovery
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+type (
+	// Observer is notified about every step of a discovery, to trace why a file location was chosen.
+	// Its methods are called synchronously, in the sequence the steps happen.
+	Observer interface {
+		// ProviderCalled is called after a provider suggested file locations.
+		ProviderCalled(event ProviderEvent)
+		// LocationChecked is called after a suggested file location was checked.
+		LocationChecked(event CheckEvent)
+		// Decided is called when a discovery finished, with the chosen file locations or the error.
+		Decided(event DecisionEvent)
+	}
+
+	// ProviderEvent describes a single provider call.
+	ProviderEvent struct {
+		// Provider is the index of the provider.
+		Provider int
+		// ProviderName is the name of the provider, or a generic name containing its index.
+		ProviderName string
+		// FileName is the file name the provider was called with.
+		FileName string
+		// Paths are the file locations suggested by the provider.
+		Paths []string
+		// Err is the error returned by the provider.
+		Err error
+		// Duration is the time the provider took.
+		Duration time.Duration
+	}
+
+	// CheckEvent describes the check of a single file location.
+	CheckEvent struct {
+		// Attempt is the checked file location. Its Err is the reason why the location was rejected, or nil if
+		// it was accepted.
+		Attempt Attempt
+		// Info is the os.FileInfo of an accepted file location, if the check provided one.
+		Info os.FileInfo
+		// Duration is the time the check took.
+		Duration time.Duration
+	}
+
+	// DecisionEvent describes the outcome of a discovery.
+	DecisionEvent struct {
+		// FileName is the file name, or the names or the pattern, the discovery was started with.
+		FileName string
+		// Paths are the chosen file locations.
+		Paths []string
+		// Err is the error the discovery returned.
+		Err error
+		// Duration is the time the whole discovery took.
+		Duration time.Duration
+	}
+)
+
+// LogObserver returns an Observer which logs every step of a discovery, along with its duration, to the given
+// logger. It is meant for verbose output, like a --verbose flag of a command line tool.
+func LogObserver(logger Logger) Observer {
+	return logObserver{logger: logger}
+}
+
+type logObserver struct {
+	logger Logger
+}
+
+func (o logObserver) ProviderCalled(event ProviderEvent) {
+	if errors.Is(event.Err, ErrSkip) {
+		o.logger.Printf("%s skipped '%s' in %v: %v", event.ProviderName, event.FileName, event.Duration, event.Err)
+
+		return
+	}
+
+	o.logger.Printf("%s suggested %v for '%s' in %v, error: %v",
+		event.ProviderName, event.Paths, event.FileName, event.Duration, event.Err)
+}
+
+func (o logObserver) LocationChecked(event CheckEvent) {
+	if event.Attempt.Err != nil {
+		o.logger.Printf("rejected '%s' in %v: %v", event.Attempt.Path, event.Duration, event.Attempt.Err)
+
+		return
+	}
+
+	o.logger.Printf("accepted '%s' in %v", event.Attempt.Path, event.Duration)
+}
+
+func (o logObserver) Decided(event DecisionEvent) {
+	if event.Err != nil {
+		o.logger.Printf("could not discover '%s' in %v", event.FileName, event.Duration)
+
+		return
+	}
+
+	o.logger.Printf("discovered %v for '%s' in %v", event.Paths, event.FileName, event.Duration)
+}
+
+func (fd *FileDiscovery) observeProvider(provider int, fileName string, paths []string, err error, start time.Time) {
+	if len(fd.observers) == 0 {
+		return
+	}
+
+	event := ProviderEvent{
+		Provider:     provider,
+		ProviderName: fd.providerName(provider),
+		FileName:     fileName,
+		Paths:        paths,
+		Err:          err,
+		Duration:     time.Since(start),
+	}
+
+	for _, observer := range fd.observers {
+		observer.ProviderCalled(event)
+	}
+}
+
+func (fd *FileDiscovery) observeCheck(attempt *Attempt, info os.FileInfo, start time.Time) {
+	if len(fd.observers) == 0 {
+		return
+	}
+
+	event := CheckEvent{Attempt: *attempt, Info: info, Duration: time.Since(start)}
+
+	for _, observer := range fd.observers {
+		observer.LocationChecked(event)
+	}
+}
+
+func (fd *FileDiscovery) observeDecision(fileName string, paths []string, err error, start time.Time) {
+	if len(fd.observers) == 0 {
+		return
+	}
+
+	event := DecisionEvent{FileName: fileName, Paths: paths, Err: err, Duration: time.Since(start)}
+
+	for _, observer := range fd.observers {
+		observer.Decided(event)
+	}
+}
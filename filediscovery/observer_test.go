@@ -0,0 +1,138 @@
+package filediscovery
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+type observerStub struct {
+	steps []string
+	check []CheckEvent
+}
+
+func (o *observerStub) ProviderCalled(event ProviderEvent) {
+	o.steps = append(o.steps, "provider "+event.ProviderName+" "+strings.Join(event.Paths, ","))
+}
+
+func (o *observerStub) LocationChecked(event CheckEvent) {
+	o.steps = append(o.steps, "checked "+event.Attempt.Path)
+	o.check = append(o.check, event)
+}
+
+func (o *observerStub) Decided(event DecisionEvent) {
+	o.steps = append(o.steps, "decided "+strings.Join(event.Paths, ","))
+}
+
+func TestWithObserver(t *testing.T) {
+
+	fsys := fstest.MapFS{"b/app.yml": &fstest.MapFile{}}
+	providers := []Provider{
+		Named("first", "", FileLocationsProvider(func(fileName string) ([]string, error) {
+			return []string{"a/" + fileName, "b/" + fileName}, nil
+		})),
+		Named("second", "", FileLocationProvider(func(fileName string) (string, error) { return "c/" + fileName, nil })),
+	}
+
+	observer := &observerStub{}
+	_, _ = NewWithProviders(providers, WithFS(fsys), WithObserver(observer)).Discover("app.yml")
+
+	expectedSteps := []string{
+		"provider first a/app.yml,b/app.yml",
+		"checked a/app.yml",
+		"checked b/app.yml",
+		"decided b/app.yml",
+	}
+
+	if !reflect.DeepEqual(expectedSteps, observer.steps) {
+		t.Fatalf("expected observed steps %q, but got %q", expectedSteps, observer.steps)
+	}
+
+	if observer.check[0].Attempt.Err == nil || observer.check[0].Info != nil {
+		t.Fatalf("expected first location to be rejected, but got: %+v", observer.check[0])
+	}
+
+	if observer.check[1].Attempt.Err != nil || observer.check[1].Info == nil {
+		t.Fatalf("expected second location to be accepted, but got: %+v", observer.check[1])
+	}
+}
+
+func TestWithObserver_observesNotFound(t *testing.T) {
+
+	providers := []FileLocationProvider{
+		func(fileName string) (string, error) { return "a/" + fileName, nil },
+	}
+
+	var decision DecisionEvent
+
+	observer := &observerFuncs{decided: func(event DecisionEvent) { decision = event }}
+	_, err := New(providers, WithFS(fstest.MapFS{}), WithObserver(observer)).DiscoverAll("app.yml")
+
+	var notFoundErr *NotFoundError
+	if !errors.As(decision.Err, &notFoundErr) || decision.Err != err {
+		t.Fatalf("expected decision to carry the returned error, but got: %v", decision.Err)
+	}
+
+	if decision.FileName != "app.yml" || decision.Paths != nil {
+		t.Fatalf("expected decision for 'app.yml' without paths, but got: %+v", decision)
+	}
+}
+
+func TestWithObserver_notifiesAllObservers(t *testing.T) {
+
+	providers := []FileLocationProvider{
+		func(fileName string) (string, error) { return "a/" + fileName, nil },
+	}
+
+	logger := &loggerStub{}
+	observer := &observerStub{}
+	_, _ = New(providers, WithFS(fstest.MapFS{}), WithLogger(logger), WithObserver(observer)).Discover("app.yml")
+
+	if len(logger.messages) != 3 {
+		t.Fatalf("expected 3 log messages, but got %q", logger.messages)
+	}
+
+	if len(observer.steps) != 3 {
+		t.Fatalf("expected 3 observed steps, but got %q", observer.steps)
+	}
+}
+
+type observerFuncs struct {
+	decided func(event DecisionEvent)
+}
+
+func (o *observerFuncs) ProviderCalled(ProviderEvent) {}
+
+func (o *observerFuncs) LocationChecked(CheckEvent) {}
+
+func (o *observerFuncs) Decided(event DecisionEvent) { o.decided(event) }
+
+func TestLogObserver(t *testing.T) {
+
+	fsys := fstest.MapFS{"b/app.yml": &fstest.MapFile{}}
+	providers := []Provider{
+		FileLocationsProvider(func(fileName string) ([]string, error) { return []string{"a/" + fileName, "b/" + fileName}, nil }),
+	}
+
+	logger := &loggerStub{}
+	_, _ = NewWithProviders(providers, WithFS(fsys), WithObserver(LogObserver(logger))).Discover("app.yml")
+
+	expectedPrefixes := []string{
+		"provider 0 suggested [a/app.yml b/app.yml] for 'app.yml' in ",
+		"rejected 'a/app.yml' in ",
+		"accepted 'b/app.yml' in ",
+		"discovered [b/app.yml] for 'app.yml' in ",
+	}
+
+	if len(expectedPrefixes) != len(logger.messages) {
+		t.Fatalf("expected %v log messages, but got %q", len(expectedPrefixes), logger.messages)
+	}
+
+	for i, prefix := range expectedPrefixes {
+		if !strings.HasPrefix(logger.messages[i], prefix) {
+			t.Fatalf("expected log message to start with %q, but got %q", prefix, logger.messages[i])
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package filediscovery
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"time"
+)
+
+// Open opens the first file location of the given fileName that can be opened, and returns the file along with its
+// path. In contrast to Discover followed by os.Open, the file that is checked is the file that is returned.
+// Locations that do not exist or are rejected are skipped, any other error stops the discovery and is returned
+// as Err of a *NotFoundError. The caller must close the returned file.
+func (fd *FileDiscovery) Open(fileName string) (fs.File, string, error) {
+	ctx := context.Background()
+	start := time.Now()
+
+	var f fs.File
+
+	attempts, found, abortErr := fd.search(ctx, fd.providerBatches(ctx, fileName), func(attempt *Attempt) bool {
+		checkStart := time.Now()
+		f, attempt.Err = fd.open(attempt.Path)
+		fd.observeCheck(attempt, nil, checkStart)
+
+		return attempt.Err == nil || !isSkippable(attempt.Err)
+	})
+
+	var err error
+	if found < 0 {
+		err = &NotFoundError{FileName: fileName, Attempts: attempts, Err: abortErr}
+	} else if attempts[found].Err != nil {
+		err = &NotFoundError{FileName: fileName, Attempts: attempts[:found+1], Err: attempts[found].Err}
+	}
+
+	if err != nil {
+		fd.observeDecision(fileName, nil, err, start)
+
+		return nil, "", err
+	}
+
+	fd.observeDecision(fileName, []string{attempts[found].Path}, nil, start)
+
+	return f, attempts[found].Path, nil
+}
+
+// ReadFile works like Open, but returns the content of the file along with its path.
+func (fd *FileDiscovery) ReadFile(fileName string) ([]byte, string, error) {
+	f, filePath, err := fd.Open(fileName)
+	if err != nil {
+		return nil, "", err
+	}
+
+	content, err := io.ReadAll(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return nil, filePath, err
+	}
+
+	return content, filePath, nil
+}
+
+func (fd *FileDiscovery) open(filePath string) (fs.File, error) {
+	if fd.noFollowSymlinks {
+		info, err := fd.fileSystem.Lstat(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		if info.Mode()&fs.ModeSymlink != 0 {
+			return nil, ErrIsSymlink
+		}
+	}
+
+	f, err := fd.fileSystem.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err == nil {
+		err = fd.accept(filePath, info)
+	}
+
+	if err != nil {
+		_ = f.Close()
+
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// isSkippable reports whether the error of a file location lets the discovery continue with the next location.
+func isSkippable(err error) bool {
+	for _, skippable := range []error{fs.ErrNotExist, fs.ErrInvalid, ErrIsDirectory, ErrIsNotDirectory, ErrIsSymlink, ErrNotMatched} {
+		if errors.Is(err, skippable) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,111 @@
+package filediscovery
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFileDiscovery_Open(t *testing.T) {
+
+	fsys := fstest.MapFS{
+		"a/app.yml/nested": &fstest.MapFile{},
+		"b/app.yml":        &fstest.MapFile{Data: []byte("b")},
+		"c/app.yml":        &fstest.MapFile{Data: []byte("c")},
+	}
+
+	providers := []Provider{
+		FileLocationsProvider(func(fileName string) ([]string, error) {
+			return []string{"missing/" + fileName, "a/" + fileName, "b/" + fileName, "c/" + fileName}, nil
+		}),
+	}
+
+	f, filePath, err := NewWithProviders(providers, WithFS(fsys)).Open("app.yml")
+	if err != nil {
+		t.Fatalf("did not expect discovery.Open to return an error, but got: %v", err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	if filePath != "b/app.yml" {
+		t.Fatalf("expected 'b/app.yml' to be opened, but got '%s'", filePath)
+	}
+
+	info, err := f.Stat()
+	if err != nil || info.Size() != 1 {
+		t.Fatalf("expected the opened file to be 'b/app.yml', but got %v, %v", info, err)
+	}
+}
+
+type openErrorFS struct {
+	fstest.MapFS
+	err error
+}
+
+func (f openErrorFS) Open(name string) (fs.File, error) {
+	if name == "locked/app.yml" {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: f.err}
+	}
+
+	return f.MapFS.Open(name)
+}
+
+func TestFileDiscovery_Open_stopsOnOtherErrors(t *testing.T) {
+
+	fsys := openErrorFS{
+		MapFS: fstest.MapFS{"b/app.yml": &fstest.MapFile{}},
+		err:   fs.ErrPermission,
+	}
+
+	providers := []Provider{
+		FileLocationsProvider(func(fileName string) ([]string, error) {
+			return []string{"missing/" + fileName, "locked/" + fileName, "b/" + fileName}, nil
+		}),
+	}
+
+	f, _, err := NewWithProviders(providers, WithFS(fsys)).Open("app.yml")
+	if f != nil {
+		t.Fatalf("expected no file to be opened")
+	}
+
+	var notFoundErr *NotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Fatalf("expected error to be a %T, but got: %v", notFoundErr, err)
+	}
+
+	if !errors.Is(notFoundErr.Err, fs.ErrPermission) {
+		t.Fatalf("expected discovery to be aborted by %v, but got %v", fs.ErrPermission, notFoundErr.Err)
+	}
+
+	if len(notFoundErr.Attempts) != 2 {
+		t.Fatalf("expected 2 attempts, but got %+v", notFoundErr.Attempts)
+	}
+}
+
+func TestFileDiscovery_ReadFile(t *testing.T) {
+
+	fsys := fstest.MapFS{
+		"b/app.yml": &fstest.MapFile{Data: []byte("content")},
+	}
+
+	providers := []Provider{
+		FileLocationsProvider(func(fileName string) ([]string, error) { return []string{"a/" + fileName, "b/" + fileName}, nil }),
+	}
+
+	discovery := NewWithProviders(providers, WithFS(fsys))
+
+	content, filePath, err := discovery.ReadFile("app.yml")
+	if err != nil {
+		t.Fatalf("did not expect discovery.ReadFile to return an error, but got: %v", err)
+	}
+
+	if filePath != "b/app.yml" || string(content) != "content" {
+		t.Fatalf("expected content of 'b/app.yml', but got '%s' from '%s'", content, filePath)
+	}
+
+	_, _, err = discovery.ReadFile("missing.yml")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected not exist error, but got: %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package filediscovery
+
+import (
+	"io/fs"
+	"os"
+	"time"
+)
+
+type (
+	// Option changes the behaviour of FileDiscovery.
+	Option func(fd *FileDiscovery)
+
+	// Logger receives log messages about the discovery process. It is implemented by *log.Logger.
+	Logger interface {
+		Printf(format string, v ...interface{})
+	}
+)
+
+// WithFS lets FileDiscovery search the given file system instead of the file system of the operating system.
+// The file locations suggested by the providers are passed to fsys as they are, so they must be valid fs.FS paths,
+// which are slash separated and unrooted, like "config/app.yml".
+// This allows to discover files in an embed.FS, an fstest.MapFS, a zip archive or any other fs.FS.
+func WithFS(fsys fs.FS) Option {
+	return func(fd *FileDiscovery) {
+		fd.fileSystem = ioFileSystem{fsys: fsys}
+	}
+}
+
+// WithStatFunc lets FileDiscovery use the given function instead of os.Stat to check file locations.
+func WithStatFunc(statFunc func(name string) (os.FileInfo, error)) Option {
+	return func(fd *FileDiscovery) {
+		fd.fileSystem = statFuncFileSystem(statFunc)
+	}
+}
+
+// WithLogger lets FileDiscovery log every step of a discovery to the given logger.
+// It is a shortcut for WithObserver(LogObserver(logger)).
+func WithLogger(logger Logger) Option {
+	return WithObserver(LogObserver(logger))
+}
+
+// AcceptDirectories lets FileDiscovery accept directories as well as files. It is a shortcut for WithTarget(TargetAny).
+func AcceptDirectories() Option {
+	return WithTarget(TargetAny)
+}
+
+// WithTarget defines the kind of file system entries FileDiscovery accepts. The default is TargetFile.
+func WithTarget(target Target) Option {
+	return func(fd *FileDiscovery) {
+		fd.target = target
+	}
+}
+
+// WithFollowSymlinks defines whether symbolic links are followed, which is the default.
+// If not, file locations which are symbolic links are rejected.
+func WithFollowSymlinks(follow bool) Option {
+	return func(fd *FileDiscovery) {
+		fd.noFollowSymlinks = !follow
+	}
+}
+
+// WithMatcher lets FileDiscovery accept only those existing file locations the given matcher returns true for.
+func WithMatcher(matcher func(filePath string, info os.FileInfo) bool) Option {
+	return func(fd *FileDiscovery) {
+		fd.matcher = matcher
+	}
+}
+
+// WithCreateDirs lets ResolveWritable create missing directories of the resolved file location with the given
+// permissions.
+func WithCreateDirs(perm os.FileMode) Option {
+	return func(fd *FileDiscovery) {
+		fd.createDirs = true
+		fd.createDirsPerm = perm
+	}
+}
+
+// WithEagerEvaluation lets FileDiscovery call all providers before the first file location is checked.
+// By default a provider is only called, if none of the file locations of the previous providers matched.
+func WithEagerEvaluation() Option {
+	return func(fd *FileDiscovery) {
+		fd.eager = true
+	}
+}
+
+// WithObserver lets FileDiscovery notify the given observer about every provider call, every checked file location
+// and the outcome of every discovery, along with their durations. Observers given by several options are notified
+// in the order of the options.
+func WithObserver(observer Observer) Option {
+	return func(fd *FileDiscovery) {
+		fd.observers = append(fd.observers, observer)
+	}
+}
+
+// WithPollInterval sets the interval in which Watch checks the file locations for changes. The default is one second.
+func WithPollInterval(interval time.Duration) Option {
+	return func(fd *FileDiscovery) {
+		fd.pollInterval = interval
+	}
+}
+
+// WithAbortOnProviderError lets FileDiscovery abort the discovery, if a provider returns an error other than ErrSkip,
+// instead of reporting the error and continuing with the next provider.
+func WithAbortOnProviderError() Option {
+	return func(fd *FileDiscovery) {
+		fd.abortOnProviderErr = true
+	}
+}
+
+// WithExpansion defines whether the built-in providers expand a leading ~ or ~user to the home directory and $VAR or
+// ${VAR} to the value of the environment variable in the directories given to them. It is enabled by default.
+// The plain FileLocationProviders WorkingDirProvider, ExecutableDirProvider, EnvVarFilePathProvider and
+// HomeConfigDirProvider never expand.
+func WithExpansion(expand bool) Option {
+	return func(fd *FileDiscovery) {
+		fd.noExpand = !expand
+	}
+}
@@ -0,0 +1,234 @@
+package filediscovery
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithFS(t *testing.T) {
+
+	fsys := fstest.MapFS{
+		"system/app.yml": &fstest.MapFile{Data: []byte("system")},
+		"user/app.yml":   &fstest.MapFile{Data: []byte("user")},
+		"dir/app.yml":    &fstest.MapFile{Mode: os.ModeDir},
+	}
+
+	providers := []Provider{
+		FileLocationsProvider(func(fileName string) ([]string, error) {
+			return []string{"project/" + fileName, "dir/" + fileName, "user/" + fileName, "system/" + fileName}, nil
+		}),
+	}
+
+	discovery := NewWithProviders(providers, WithFS(fsys))
+
+	result, err := discovery.Discover("app.yml")
+	if err != nil {
+		t.Fatalf("did not expect discovery.Discover to return an error, but got: %v", err)
+	}
+
+	if result != "user/app.yml" {
+		t.Fatalf("expected discovery.Discover to return 'user/app.yml', but got '%s'", result)
+	}
+
+	_, err = discovery.Discover("missing.yml")
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected discovery.Discover to return a not exist error, but got: %v", err)
+	}
+}
+
+func TestWithStatFunc(t *testing.T) {
+
+	var statted []string
+	statFunc := func(name string) (os.FileInfo, error) {
+		statted = append(statted, name)
+		if name == "/b/app.yml" {
+			return fstest.MapFS{"app.yml": &fstest.MapFile{}}.Stat("app.yml")
+		}
+
+		return nil, os.ErrNotExist
+	}
+
+	providers := []Provider{
+		FileLocationsProvider(func(fileName string) ([]string, error) { return []string{"/a/" + fileName, "/b/" + fileName}, nil }),
+	}
+
+	result, err := NewWithProviders(providers, WithStatFunc(statFunc)).Discover("app.yml")
+	if err != nil {
+		t.Fatalf("did not expect discovery.Discover to return an error, but got: %v", err)
+	}
+
+	if result != "/b/app.yml" {
+		t.Fatalf("expected discovery.Discover to return '/b/app.yml', but got '%s'", result)
+	}
+
+	if !reflect.DeepEqual([]string{"/a/app.yml", "/b/app.yml"}, statted) {
+		t.Fatalf("expected stat func to be called for all locations, but got %v", statted)
+	}
+}
+
+type loggerStub struct {
+	messages []string
+}
+
+func (l *loggerStub) Printf(format string, v ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(format, v...))
+}
+
+func TestWithLogger(t *testing.T) {
+
+	fsys := fstest.MapFS{"b/app.yml": &fstest.MapFile{}}
+	providers := []Provider{
+		FileLocationsProvider(func(fileName string) ([]string, error) { return []string{"a/" + fileName, "b/" + fileName}, nil }),
+	}
+
+	logger := &loggerStub{}
+	_, _ = NewWithProviders(providers, WithFS(fsys), WithLogger(logger)).Discover("app.yml")
+
+	expectedPrefixes := []string{
+		"provider 0 suggested [a/app.yml b/app.yml] for 'app.yml' in ",
+		"rejected 'a/app.yml' in ",
+		"accepted 'b/app.yml' in ",
+		"discovered [b/app.yml] for 'app.yml' in ",
+	}
+
+	if len(expectedPrefixes) != len(logger.messages) {
+		t.Fatalf("expected %v log messages, but got %q", len(expectedPrefixes), logger.messages)
+	}
+
+	for i, prefix := range expectedPrefixes {
+		if !strings.HasPrefix(logger.messages[i], prefix) {
+			t.Fatalf("expected log message to start with %q, but got %q", prefix, logger.messages[i])
+		}
+	}
+}
+
+func TestAcceptDirectories(t *testing.T) {
+
+	fsys := fstest.MapFS{"dir/app.yml/config": &fstest.MapFile{}}
+	providers := []FileLocationProvider{
+		func(fileName string) (string, error) { return "dir/" + fileName, nil },
+	}
+
+	_, err := New(providers, WithFS(fsys)).Discover("app.yml")
+	if !errors.Is(err, ErrIsDirectory) {
+		t.Fatalf("expected directory to be rejected by default, but got: %v", err)
+	}
+
+	result, err := New(providers, WithFS(fsys), AcceptDirectories()).Discover("app.yml")
+	if err != nil {
+		t.Fatalf("did not expect discovery.Discover to return an error, but got: %v", err)
+	}
+
+	if result != "dir/app.yml" {
+		t.Fatalf("expected discovery.Discover to return 'dir/app.yml', but got '%s'", result)
+	}
+}
+
+func TestWithFollowSymlinks(t *testing.T) {
+
+	dirs := createTestDirs(t, 1)
+	defer removeTestDirs(t, dirs)
+
+	targetFilePath := filepath.Join(dirs[0], "target.yml")
+	linkFilePath := filepath.Join(dirs[0], "app.yml")
+	createTestFile(t, targetFilePath)
+
+	err := os.Symlink(targetFilePath, linkFilePath)
+	if err != nil {
+		t.Skipf("symbolic links are not supported: %v", err)
+	}
+
+	providers := []FileLocationProvider{
+		func(fileName string) (string, error) { return filepath.Join(dirs[0], fileName), nil },
+	}
+
+	result, err := New(providers).Discover("app.yml")
+	if err != nil || result != linkFilePath {
+		t.Fatalf("expected symbolic link to be followed by default, but got '%s', %v", result, err)
+	}
+
+	_, err = New(providers, WithFollowSymlinks(false)).Discover("app.yml")
+	if !errors.Is(err, ErrIsSymlink) {
+		t.Fatalf("expected symbolic link to be rejected, but got: %v", err)
+	}
+}
+
+func TestWithMatcher(t *testing.T) {
+
+	fsys := fstest.MapFS{
+		"a/app.yml": &fstest.MapFile{},
+		"b/app.yml": &fstest.MapFile{Data: []byte("content")},
+	}
+	providers := []Provider{
+		FileLocationsProvider(func(fileName string) ([]string, error) { return []string{"a/" + fileName, "b/" + fileName}, nil }),
+	}
+
+	nonEmpty := func(filePath string, info os.FileInfo) bool { return info.Size() > 0 }
+
+	discovery := NewWithProviders(providers, WithFS(fsys), WithMatcher(nonEmpty))
+
+	result, err := discovery.Discover("app.yml")
+	if err != nil {
+		t.Fatalf("did not expect discovery.Discover to return an error, but got: %v", err)
+	}
+
+	if result != "b/app.yml" {
+		t.Fatalf("expected discovery.Discover to return 'b/app.yml', but got '%s'", result)
+	}
+
+	var notFoundErr *NotFoundError
+	_, err = NewWithProviders(providers[:1], WithFS(fstest.MapFS{"a/app.yml": &fstest.MapFile{}}), WithMatcher(nonEmpty)).Discover("app.yml")
+	if !errors.As(err, &notFoundErr) || !errors.Is(notFoundErr.Attempts[0].Err, ErrNotMatched) {
+		t.Fatalf("expected file location to be rejected by matcher, but got: %v", err)
+	}
+}
+
+func TestWithEagerEvaluation(t *testing.T) {
+
+	mock1, _ := newFileLocationProviderMock()
+	mock2, provider2 := newFileLocationProviderMock()
+
+	providers := []FileLocationProvider{
+		mock1.GetFunc("a/test-file", nil),
+		provider2,
+	}
+
+	discovery := New(providers, WithFS(fstest.MapFS{"a/test-file": &fstest.MapFile{}}), WithEagerEvaluation())
+
+	result, err := discovery.Discover("test-file")
+	if err != nil || result != "a/test-file" {
+		t.Fatalf("expected discovery.Discover to return 'a/test-file', but got '%s', %v", result, err)
+	}
+
+	if !mock1.WasCalled() || !mock2.WasCalled() {
+		t.Fatalf("expected all providers to be called, but they were not")
+	}
+}
+
+func TestWithAbortOnProviderError(t *testing.T) {
+
+	errStub := errors.New("stub-error")
+	mock, provider := newFileLocationProviderMock()
+	providers := []Provider{
+		FileLocationProvider(func(fileName string) (string, error) { return "", fmt.Errorf("unset: %w", ErrSkip) }),
+		Named("failing", "", FileLocationProvider(func(fileName string) (string, error) { return "", errStub })),
+		provider,
+	}
+
+	_, err := NewWithProviders(providers, WithAbortOnProviderError()).Discover("test-file")
+
+	var notFoundErr *NotFoundError
+	if !errors.As(err, &notFoundErr) || !errors.Is(notFoundErr.Err, errStub) {
+		t.Fatalf("expected discovery to be aborted with the provider error, but got: %v", err)
+	}
+
+	if mock.WasCalled() {
+		t.Fatalf("expected provider after the failing provider not to be called, but it was")
+	}
+}
@@ -1,15 +1,20 @@
 package filediscovery
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/user"
 	"path"
 	"path/filepath"
+	"strings"
 )
 
 var workingDirProviderFunc = os.Getwd
 
+// envLookupFunc looks up the environment variables of all providers.
+var envLookupFunc = os.LookupEnv
+
 // WorkingDirProvider provides the working directory as a possible file location
 func WorkingDirProvider(subFolders ...string) FileLocationProvider {
 
@@ -25,6 +30,128 @@ func WorkingDirProvider(subFolders ...string) FileLocationProvider {
 	}
 }
 
+// NamedWorkingDirProvider works like WorkingDirProvider, but is named "working dir" in diagnostics.
+// Environment variables in the sub folders are expanded.
+func NamedWorkingDirProvider(subFolders ...string) Provider {
+	locate := func(ctx context.Context, fileName string) ([]string, error) {
+		subFoldersPath, err := expandSubFolders(ctx, subFolders...)
+		if err != nil {
+			return nil, err
+		}
+
+		return WorkingDirProvider(subFoldersPath).Locate(fileName)
+	}
+
+	return Named("working dir", describePath("<working dir>", subFolders...), FileLocationContextProvider(locate))
+}
+
+// ExplicitPathProvider provides the given filePath as file location, regardless of the file name to discover.
+// It is meant for a path given explicitly by the user, like a --config flag. If filePath is empty, it provides no
+// file location. Wrap it with Required to fail the discovery, if the given file does not exist.
+func ExplicitPathProvider(filePath string) Provider {
+	locate := func(fileName string) ([]string, error) {
+		_ = fileName
+		if filePath == "" {
+			return nil, nil
+		}
+
+		return []string{filePath}, nil
+	}
+
+	return Named("explicit path", filePath, FileLocationsProvider(locate))
+}
+
+// ParentDirsSearch configures the upward directory search of ParentDirsSearchProvider.
+type ParentDirsSearch struct {
+	// StartDir is the first directory to search in. If empty, the working directory is used. A leading ~ and
+	// environment variables are expanded, a relative StartDir is resolved against the working directory.
+	StartDir string
+	// BoundaryMarkers are names of files or directories, like ".git", which mark the top most directory to search in.
+	BoundaryMarkers []string
+	// CeilingDir is the top most directory to search in. If empty, the search continues up to the file system root.
+	// It is expanded and resolved like StartDir.
+	CeilingDir string
+	// SubFolders are appended to every searched directory.
+	SubFolders []string
+}
+
+// ParentDirsProvider provides the working directory and all of its parent directories up to the file system root as
+// possible file locations, nearest directory first.
+func ParentDirsProvider(subFolders ...string) Provider {
+	return ParentDirsSearchProvider(ParentDirsSearch{SubFolders: subFolders})
+}
+
+// ParentDirsSearchProvider provides the start directory of the given search and all of its parent directories as
+// possible file locations, nearest directory first. The search ends at the file system root, at the ceiling directory
+// or at the first directory containing one of the boundary markers, whichever comes first.
+func ParentDirsSearchProvider(search ParentDirsSearch) Provider {
+	locate := func(ctx context.Context, fileName string) ([]string, error) {
+		dir, err := absDir(ctx, search.StartDir)
+		if err != nil {
+			return nil, err
+		}
+
+		ceilingDir := ""
+		if search.CeilingDir != "" {
+			ceilingDir, err = absDir(ctx, search.CeilingDir)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		subFoldersPath, err := expandSubFolders(ctx, search.SubFolders...)
+		if err != nil {
+			return nil, err
+		}
+
+		var filePaths []string
+
+		for {
+			filePaths = append(filePaths, filepath.Join(dir, subFoldersPath, fileName))
+
+			parentDir := filepath.Dir(dir)
+			if dir == ceilingDir || parentDir == dir || containsAny(dir, search.BoundaryMarkers) {
+				return filePaths, nil
+			}
+
+			dir = parentDir
+		}
+	}
+
+	return Named("parent dirs", describeParentDirsSearch(search), FileLocationContextProvider(locate))
+}
+
+// absDir returns the expanded dir resolved against the working directory. An empty dir denotes the working directory.
+func absDir(ctx context.Context, dir string) (string, error) {
+	dir, err := expandPath(ctx, dir)
+	if err != nil {
+		return "", err
+	}
+
+	if filepath.IsAbs(dir) {
+		return filepath.Clean(dir), nil
+	}
+
+	wd, err := workingDirProviderFunc()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(wd, dir), nil
+}
+
+var boundaryMarkerStatFunc = os.Stat
+
+func containsAny(dir string, names []string) bool {
+	for _, name := range names {
+		if _, err := boundaryMarkerStatFunc(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
 var executableDirProviderFunc = os.Executable
 
 // ExecutableDirProvider provides the executables directory as a possible file location
@@ -43,25 +170,190 @@ func ExecutableDirProvider(subFolders ...string) FileLocationProvider {
 	}
 }
 
-var envVarFilePathLookupFunc = os.LookupEnv
+// NamedExecutableDirProvider works like ExecutableDirProvider, but is named "executable dir" in diagnostics.
+// Environment variables in the sub folders are expanded.
+func NamedExecutableDirProvider(subFolders ...string) Provider {
+	locate := func(ctx context.Context, fileName string) ([]string, error) {
+		subFoldersPath, err := expandSubFolders(ctx, subFolders...)
+		if err != nil {
+			return nil, err
+		}
+
+		return ExecutableDirProvider(subFoldersPath).Locate(fileName)
+	}
+
+	return Named("executable dir", describePath("<executable dir>", subFolders...), FileLocationContextProvider(locate))
+}
+
+var systemConfigDirPrefixes = []string{"/etc", "/usr/local/etc", "../etc"}
+
+// SystemConfigDirProvider provides the system wide configuration directories /etc, /usr/local/etc and, following the
+// FHS layout, the etc directory next to the directory of the executable, like /opt/app/etc for /opt/app/bin/app,
+// as possible file locations, in that order.
+func SystemConfigDirProvider(subFolders ...string) Provider {
+	return SystemConfigDirPrefixesProvider(systemConfigDirPrefixes, subFolders...)
+}
+
+// SystemConfigDirPrefixesProvider works like SystemConfigDirProvider, but provides the given prefixes instead of
+// the default system configuration directories. A leading ~ and environment variables in the prefixes are expanded,
+// relative prefixes are resolved against the directory of the executable.
+func SystemConfigDirPrefixesProvider(prefixes []string, subFolders ...string) Provider {
+	locate := func(ctx context.Context, fileName string) ([]string, error) {
+		subFoldersPath, err := expandSubFolders(ctx, subFolders...)
+		if err != nil {
+			return nil, err
+		}
+
+		var filePaths []string
+
+		for _, prefix := range prefixes {
+			prefix, err := expandPath(ctx, prefix)
+			if err != nil {
+				return filePaths, err
+			}
+
+			if !filepath.IsAbs(prefix) {
+				executable, err := executableDirProviderFunc()
+				if err != nil {
+					return filePaths, err
+				}
+
+				prefix = filepath.Join(filepath.Dir(executable), prefix)
+			}
+
+			filePaths = append(filePaths, filepath.Join(prefix, subFoldersPath, fileName))
+		}
+
+		return filePaths, nil
+	}
+
+	return Named("system config dir", describePath("<system config dir>", subFolders...), FileLocationContextProvider(locate))
+}
 
 // EnvVarFilePathProvider provides a filePath in the given environment variable.
 // In contrast to other FileLocationProviders, this file location provider expects a complete filePath in the given
-// environment variable.
+// environment variable. If the variable is unset or empty, the provider is skipped.
 func EnvVarFilePathProvider(envVar string) FileLocationProvider {
 	return func(fileName string) (string, error) {
 		_ = fileName
-		if envConfigFile, ok := envVarFilePathLookupFunc(envVar); ok {
+		if envConfigFile, ok := envLookupFunc(envVar); ok && envConfigFile != "" {
 			return envConfigFile, nil
 		}
 
-		return "", fmt.Errorf("env var '%s' not defined", envVar)
+		return "", fmt.Errorf("env var '%s' not defined: %w", envVar, ErrSkip)
 	}
 }
 
+// NamedEnvVarFilePathProvider works like EnvVarFilePathProvider, but is named "env var <envVar>" in diagnostics.
+// A leading ~ and environment variables in the filePath are expanded, so a value like ~/configs/app.yml works.
+func NamedEnvVarFilePathProvider(envVar string) Provider {
+	locate := func(ctx context.Context, fileName string) ([]string, error) {
+		filePath, err := EnvVarFilePathProvider(envVar)(fileName)
+		if err != nil {
+			return nil, err
+		}
+
+		filePath, err = expandPath(ctx, filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{filePath}, nil
+	}
+
+	return Named("env var "+envVar, "$"+envVar, FileLocationContextProvider(locate))
+}
+
+// EnvVarDirProvider provides the directory given in the environment variable as a possible file location, like
+// $MYAPP_HOME/conf for EnvVarDirProvider("MYAPP_HOME", "conf"). The directory is expanded like the one of
+// NamedEnvVarFilePathProvider. If the variable is unset or empty, the provider is skipped.
+func EnvVarDirProvider(envVar string, subFolders ...string) Provider {
+	locate := func(ctx context.Context, fileName string) ([]string, error) {
+		dir, ok := envLookupFunc(envVar)
+		if !ok || dir == "" {
+			return nil, fmt.Errorf("env var '%s' not defined: %w", envVar, ErrSkip)
+		}
+
+		dir, err := expandPath(ctx, dir)
+		if err != nil {
+			return nil, err
+		}
+
+		subFoldersPath, err := expandSubFolders(ctx, subFolders...)
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{filepath.Join(dir, subFoldersPath, fileName)}, nil
+	}
+
+	return Named("env var "+envVar, describePath("$"+envVar, subFolders...), FileLocationContextProvider(locate))
+}
+
+// PathList configures the search list of PathListProvider.
+type PathList struct {
+	// EnvVar is the environment variable holding the list of directories, separated by os.PathListSeparator.
+	EnvVar string
+	// EmptyIsWorkingDir lets empty elements of the list, like in "/a::/b", denote the working directory, as they do
+	// in PATH. Otherwise they are ignored.
+	EmptyIsWorkingDir bool
+	// SubFolders are appended to every directory of the list.
+	SubFolders []string
+}
+
+// PathListProvider provides every directory of the search list given in an environment variable, like
+// MYAPP_CONFIG_PATH=/a:/b:/c, as a possible file location, in order of the list. Every directory of the list is
+// expanded like the one of NamedEnvVarFilePathProvider. If the variable is unset or empty, the provider is skipped.
+func PathListProvider(list PathList) Provider {
+	locate := func(ctx context.Context, fileName string) ([]string, error) {
+		value, ok := envLookupFunc(list.EnvVar)
+		if !ok || value == "" {
+			return nil, fmt.Errorf("env var '%s' not defined: %w", list.EnvVar, ErrSkip)
+		}
+
+		subFoldersPath, err := expandSubFolders(ctx, list.SubFolders...)
+		if err != nil {
+			return nil, err
+		}
+
+		var filePaths []string
+
+		for _, element := range filepath.SplitList(value) {
+			dir, err := pathListDir(ctx, element, list.EmptyIsWorkingDir)
+			if err != nil {
+				return filePaths, err
+			}
+
+			if dir == "" {
+				continue
+			}
+
+			filePaths = append(filePaths, filepath.Join(dir, subFoldersPath, fileName))
+		}
+
+		return filePaths, nil
+	}
+
+	return Named("path list "+list.EnvVar, describePath("$"+list.EnvVar, list.SubFolders...), FileLocationContextProvider(locate))
+}
+
+// pathListDir returns the expanded directory of the given element of a search list. An empty element denotes the
+// working directory if emptyIsWorkingDir is set, otherwise an empty directory is returned.
+func pathListDir(ctx context.Context, element string, emptyIsWorkingDir bool) (string, error) {
+	if element != "" {
+		return expandPath(ctx, element)
+	}
+
+	if !emptyIsWorkingDir {
+		return "", nil
+	}
+
+	return workingDirProviderFunc()
+}
+
 var homeFolderLookupFunc = user.Current
 
-// HomeConfigDirProvider provides the working directory as a possible file location
+// HomeConfigDirProvider provides the home directory of the current user as a possible file location
 func HomeConfigDirProvider(subFolders ...string) FileLocationProvider {
 
 	return func(fileName string) (string, error) {
@@ -76,6 +368,116 @@ func HomeConfigDirProvider(subFolders ...string) FileLocationProvider {
 	}
 }
 
+// NamedHomeConfigDirProvider works like HomeConfigDirProvider, but is named "home config dir" in diagnostics.
+// Environment variables in the sub folders are expanded.
+func NamedHomeConfigDirProvider(subFolders ...string) Provider {
+	locate := func(ctx context.Context, fileName string) ([]string, error) {
+		subFoldersPath, err := expandSubFolders(ctx, subFolders...)
+		if err != nil {
+			return nil, err
+		}
+
+		return HomeConfigDirProvider(subFoldersPath).Locate(fileName)
+	}
+
+	return Named("home config dir", describePath("~", subFolders...), FileLocationContextProvider(locate))
+}
+
+// XDGConfigHomeProvider provides $XDG_CONFIG_HOME as a possible file location.
+// If the variable is unset, empty or not an absolute path, $HOME/.config is used as defined by the
+// XDG Base Directory Specification.
+func XDGConfigHomeProvider(subFolders ...string) Provider {
+	return xdgHomeDirProvider("XDG config home", "XDG_CONFIG_HOME", ".config", subFolders)
+}
+
+// XDGDataHomeProvider provides $XDG_DATA_HOME as a possible file location.
+// If the variable is unset, empty or not an absolute path, $HOME/.local/share is used.
+func XDGDataHomeProvider(subFolders ...string) Provider {
+	return xdgHomeDirProvider("XDG data home", "XDG_DATA_HOME", path.Join(".local", "share"), subFolders)
+}
+
+// XDGCacheHomeProvider provides $XDG_CACHE_HOME as a possible file location.
+// If the variable is unset, empty or not an absolute path, $HOME/.cache is used.
+func XDGCacheHomeProvider(subFolders ...string) Provider {
+	return xdgHomeDirProvider("XDG cache home", "XDG_CACHE_HOME", ".cache", subFolders)
+}
+
+// XDGConfigDirsProvider provides every directory of $XDG_CONFIG_DIRS as a possible file location, in order of
+// preference. If the variable is unset or empty, /etc/xdg is used. Relative entries are ignored.
+func XDGConfigDirsProvider(subFolders ...string) Provider {
+	return xdgDirsProvider("XDG config dirs", "XDG_CONFIG_DIRS", []string{"/etc/xdg"}, subFolders)
+}
+
+// XDGDataDirsProvider provides every directory of $XDG_DATA_DIRS as a possible file location, in order of
+// preference. If the variable is unset or empty, /usr/local/share and /usr/share are used. Relative entries are ignored.
+func XDGDataDirsProvider(subFolders ...string) Provider {
+	return xdgDirsProvider("XDG data dirs", "XDG_DATA_DIRS", []string{"/usr/local/share", "/usr/share"}, subFolders)
+}
+
+func xdgHomeDirProvider(name string, envVar string, defaultHomeSubFolder string, subFolders []string) Provider {
+	locate := func(ctx context.Context, fileName string) ([]string, error) {
+		dir, ok := envLookupFunc(envVar)
+		if !ok || !path.IsAbs(dir) {
+			usr, err := homeFolderLookupFunc()
+			if err != nil {
+				return nil, err
+			}
+
+			dir = path.Join(usr.HomeDir, defaultHomeSubFolder)
+		}
+
+		subFoldersPath, err := expandSubFolders(ctx, subFolders...)
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{path.Join(dir, subFoldersPath, fileName)}, nil
+	}
+
+	return Named(name, describePath("$"+envVar, subFolders...), FileLocationContextProvider(locate))
+}
+
+func xdgDirsProvider(name string, envVar string, defaultDirs []string, subFolders []string) Provider {
+	locate := func(ctx context.Context, fileName string) ([]string, error) {
+		dirs := defaultDirs
+		if value, ok := envLookupFunc(envVar); ok && value != "" {
+			dirs = strings.Split(value, ":")
+		}
+
+		subFoldersPath, err := expandSubFolders(ctx, subFolders...)
+		if err != nil {
+			return nil, err
+		}
+
+		var filePaths []string
+
+		for _, dir := range dirs {
+			if !path.IsAbs(dir) {
+				continue
+			}
+
+			filePaths = append(filePaths, path.Join(dir, subFoldersPath, fileName))
+		}
+
+		return filePaths, nil
+	}
+
+	return Named(name, describePath("$"+envVar, subFolders...), FileLocationContextProvider(locate))
+}
+
+func describePath(dir string, subFolders ...string) string {
+	return path.Join(dir, createPath(subFolders...))
+}
+
+func describeParentDirsSearch(search ParentDirsSearch) string {
+	startDir := search.StartDir
+	if startDir == "" {
+		startDir = "<working dir>"
+	}
+
+	return describePath("<parent dirs of "+startDir+">", search.SubFolders...)
+}
+
 func createPath(subFolders ...string) string {
 	subFoldersPath := ""
 	for _, subfolder := range subFolders {
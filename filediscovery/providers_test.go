@@ -3,6 +3,8 @@ package filediscovery
 import (
 	"os"
 	"path"
+	"reflect"
+	"runtime"
 	"testing"
 
 	"errors"
@@ -223,3 +225,471 @@ func TestHomeConfigDirProvider_UserLookupReturnsError(t *testing.T) {
 		t.Fatalf("expected provider to return %v, but got %v", errorStub, err)
 	}
 }
+
+func stubXDGEnv(t *testing.T, env map[string]string, homeDir string) {
+	t.Helper()
+
+	stubEnv(t, env)
+
+	originalHomeFolderLookupFunc := homeFolderLookupFunc
+	homeFolderLookupFunc = func() (*user.User, error) {
+		return &user.User{HomeDir: homeDir}, nil
+	}
+	t.Cleanup(func() { homeFolderLookupFunc = originalHomeFolderLookupFunc })
+}
+
+func stubEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+
+	originalEnvLookupFunc := envLookupFunc
+	envLookupFunc = func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+	t.Cleanup(func() { envLookupFunc = originalEnvLookupFunc })
+}
+
+func TestXDGHomeProviders(t *testing.T) {
+	const testFileName = "testfile"
+	const homeDir = "/home/test"
+
+	testDataSet := map[string]struct {
+		ProviderFunc func(subFolders ...string) Provider
+		Env          map[string]string
+		ExpectedPath string
+	}{
+		"config home unset": {
+			ProviderFunc: XDGConfigHomeProvider,
+			Env:          map[string]string{},
+			ExpectedPath: "/home/test/.config/myapp/testfile",
+		},
+		"config home empty": {
+			ProviderFunc: XDGConfigHomeProvider,
+			Env:          map[string]string{"XDG_CONFIG_HOME": ""},
+			ExpectedPath: "/home/test/.config/myapp/testfile",
+		},
+		"config home relative": {
+			ProviderFunc: XDGConfigHomeProvider,
+			Env:          map[string]string{"XDG_CONFIG_HOME": "relative/config"},
+			ExpectedPath: "/home/test/.config/myapp/testfile",
+		},
+		"config home set": {
+			ProviderFunc: XDGConfigHomeProvider,
+			Env:          map[string]string{"XDG_CONFIG_HOME": "/xdg/config"},
+			ExpectedPath: "/xdg/config/myapp/testfile",
+		},
+		"data home unset": {
+			ProviderFunc: XDGDataHomeProvider,
+			Env:          map[string]string{},
+			ExpectedPath: "/home/test/.local/share/myapp/testfile",
+		},
+		"data home set": {
+			ProviderFunc: XDGDataHomeProvider,
+			Env:          map[string]string{"XDG_DATA_HOME": "/xdg/data"},
+			ExpectedPath: "/xdg/data/myapp/testfile",
+		},
+		"cache home unset": {
+			ProviderFunc: XDGCacheHomeProvider,
+			Env:          map[string]string{},
+			ExpectedPath: "/home/test/.cache/myapp/testfile",
+		},
+		"cache home set": {
+			ProviderFunc: XDGCacheHomeProvider,
+			Env:          map[string]string{"XDG_CACHE_HOME": "/xdg/cache"},
+			ExpectedPath: "/xdg/cache/myapp/testfile",
+		},
+	}
+
+	for testCaseName, testData := range testDataSet {
+		t.Run(testCaseName, func(t *testing.T) {
+			stubXDGEnv(t, testData.Env, homeDir)
+
+			provider := testData.ProviderFunc("myapp")
+			result, err := provider.Locate(testFileName)
+			if err != nil {
+				t.Fatalf("Did not expect provider to return an error, but got: %v", err)
+			}
+
+			if !reflect.DeepEqual([]string{testData.ExpectedPath}, result) {
+				t.Fatalf("expected provider to return path %v, but got: %v", []string{testData.ExpectedPath}, result)
+			}
+		})
+	}
+}
+
+func TestXDGHomeProviders_UserLookupReturnsError(t *testing.T) {
+	stubXDGEnv(t, map[string]string{}, "")
+
+	errorStub := errors.New("error-stub")
+	homeFolderLookupFunc = func() (*user.User, error) {
+		return nil, errorStub
+	}
+
+	provider := XDGConfigHomeProvider()
+	_, err := provider.Locate("testfile")
+
+	if errorStub != err {
+		t.Fatalf("expected provider to return %v, but got %v", errorStub, err)
+	}
+}
+
+func TestXDGDirsProviders(t *testing.T) {
+	const testFileName = "testfile"
+
+	testDataSet := map[string]struct {
+		ProviderFunc  func(subFolders ...string) Provider
+		Env           map[string]string
+		ExpectedPaths []string
+	}{
+		"config dirs unset": {
+			ProviderFunc:  XDGConfigDirsProvider,
+			Env:           map[string]string{},
+			ExpectedPaths: []string{"/etc/xdg/myapp/testfile"},
+		},
+		"config dirs empty": {
+			ProviderFunc:  XDGConfigDirsProvider,
+			Env:           map[string]string{"XDG_CONFIG_DIRS": ""},
+			ExpectedPaths: []string{"/etc/xdg/myapp/testfile"},
+		},
+		"config dirs in order": {
+			ProviderFunc:  XDGConfigDirsProvider,
+			Env:           map[string]string{"XDG_CONFIG_DIRS": "/b:/a"},
+			ExpectedPaths: []string{"/b/myapp/testfile", "/a/myapp/testfile"},
+		},
+		"config dirs ignores relative and empty entries": {
+			ProviderFunc:  XDGConfigDirsProvider,
+			Env:           map[string]string{"XDG_CONFIG_DIRS": "relative::/a"},
+			ExpectedPaths: []string{"/a/myapp/testfile"},
+		},
+		"data dirs unset": {
+			ProviderFunc:  XDGDataDirsProvider,
+			Env:           map[string]string{},
+			ExpectedPaths: []string{"/usr/local/share/myapp/testfile", "/usr/share/myapp/testfile"},
+		},
+		"data dirs set": {
+			ProviderFunc:  XDGDataDirsProvider,
+			Env:           map[string]string{"XDG_DATA_DIRS": "/opt/share:/usr/share"},
+			ExpectedPaths: []string{"/opt/share/myapp/testfile", "/usr/share/myapp/testfile"},
+		},
+	}
+
+	for testCaseName, testData := range testDataSet {
+		t.Run(testCaseName, func(t *testing.T) {
+			stubXDGEnv(t, testData.Env, "/home/test")
+
+			provider := testData.ProviderFunc("myapp")
+			result, err := provider.Locate(testFileName)
+			if err != nil {
+				t.Fatalf("Did not expect provider to return an error, but got: %v", err)
+			}
+
+			if !reflect.DeepEqual(testData.ExpectedPaths, result) {
+				t.Fatalf("expected provider to return paths %v, but got: %v", testData.ExpectedPaths, result)
+			}
+		})
+	}
+}
+
+func TestParentDirsProvider(t *testing.T) {
+	const testFileName = "testfile"
+
+	originalWorkingDirProviderFunc := workingDirProviderFunc
+	defer func() { workingDirProviderFunc = originalWorkingDirProviderFunc }()
+
+	workingDirProviderFunc = func() (string, error) {
+		return "/a/b", nil
+	}
+
+	provider := ParentDirsProvider("sub")
+	result, err := provider.Locate(testFileName)
+	if err != nil {
+		t.Fatalf("Did not expect provider to return an error, but got: %v", err)
+	}
+
+	expected := []string{
+		filepath.Join("/a/b", "sub", testFileName),
+		filepath.Join("/a", "sub", testFileName),
+		filepath.Join("/", "sub", testFileName),
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("expected provider to return paths %v, but got: %v", expected, result)
+	}
+}
+
+func TestParentDirsProvider_error(t *testing.T) {
+	originalWorkingDirProviderFunc := workingDirProviderFunc
+	defer func() { workingDirProviderFunc = originalWorkingDirProviderFunc }()
+
+	errorStub := errors.New("error-stub")
+	workingDirProviderFunc = func() (string, error) {
+		return "", errorStub
+	}
+
+	provider := ParentDirsProvider()
+	_, err := provider.Locate("testfile")
+
+	if errorStub != err {
+		t.Fatalf("did expect provider to return stubbed error %v, but got %v", errorStub, err)
+	}
+}
+
+func TestParentDirsSearchProvider(t *testing.T) {
+	const testFileName = "testfile"
+
+	rootDir := filepath.Join(os.TempDir(), "filediscovery-test")
+	startDir := filepath.Join(rootDir, "repo", "a", "b")
+
+	originalWorkingDirProviderFunc := workingDirProviderFunc
+	originalBoundaryMarkerStatFunc := boundaryMarkerStatFunc
+	t.Cleanup(func() {
+		workingDirProviderFunc = originalWorkingDirProviderFunc
+		boundaryMarkerStatFunc = originalBoundaryMarkerStatFunc
+	})
+
+	workingDirProviderFunc = func() (string, error) {
+		return filepath.Join(rootDir, "repo", "a"), nil
+	}
+	boundaryMarkerStatFunc = func(name string) (os.FileInfo, error) {
+		if name == filepath.Join(rootDir, "repo", ".git") {
+			return nil, nil
+		}
+
+		return nil, os.ErrNotExist
+	}
+
+	testDataSet := map[string]struct {
+		Search        ParentDirsSearch
+		ExpectedPaths []string
+	}{
+		"boundary marker": {
+			Search: ParentDirsSearch{StartDir: startDir, BoundaryMarkers: []string{".hg", ".git"}},
+			ExpectedPaths: []string{
+				filepath.Join(rootDir, "repo", "a", "b", testFileName),
+				filepath.Join(rootDir, "repo", "a", testFileName),
+				filepath.Join(rootDir, "repo", testFileName),
+			},
+		},
+		"ceiling dir": {
+			Search: ParentDirsSearch{StartDir: startDir, CeilingDir: filepath.Join(rootDir, "repo", "a")},
+			ExpectedPaths: []string{
+				filepath.Join(rootDir, "repo", "a", "b", testFileName),
+				filepath.Join(rootDir, "repo", "a", testFileName),
+			},
+		},
+		"ceiling dir and sub folders": {
+			Search: ParentDirsSearch{StartDir: startDir, CeilingDir: startDir, SubFolders: []string{"conf"}},
+			ExpectedPaths: []string{
+				filepath.Join(rootDir, "repo", "a", "b", "conf", testFileName),
+			},
+		},
+		"relative start and ceiling dir": {
+			Search: ParentDirsSearch{StartDir: "b", CeilingDir: ".."},
+			ExpectedPaths: []string{
+				filepath.Join(rootDir, "repo", "a", "b", testFileName),
+				filepath.Join(rootDir, "repo", "a", testFileName),
+				filepath.Join(rootDir, "repo", testFileName),
+			},
+		},
+	}
+
+	for testCaseName, testData := range testDataSet {
+		t.Run(testCaseName, func(t *testing.T) {
+			provider := ParentDirsSearchProvider(testData.Search)
+			result, err := provider.Locate(testFileName)
+			if err != nil {
+				t.Fatalf("Did not expect provider to return an error, but got: %v", err)
+			}
+
+			if !reflect.DeepEqual(testData.ExpectedPaths, result) {
+				t.Fatalf("expected provider to return paths %v, but got: %v", testData.ExpectedPaths, result)
+			}
+		})
+	}
+}
+
+func TestEnvVarFilePathProvider_skipsUnsetEnvVar(t *testing.T) {
+	stubEnv(t, map[string]string{"EMPTY": ""})
+
+	for _, envVar := range []string{"EMPTY", "UNSET"} {
+		if _, err := EnvVarFilePathProvider(envVar)("app.yml"); !errors.Is(err, ErrSkip) {
+			t.Fatalf("expected provider to be skipped for %s, but got: %v", envVar, err)
+		}
+	}
+}
+
+func TestNamedProviders_locateLikeTheirPlainCounterparts(t *testing.T) {
+	originalHomeFolderLookupFunc := homeFolderLookupFunc
+	homeFolderLookupFunc = func() (*user.User, error) { return &user.User{HomeDir: "/home/me"}, nil }
+	t.Cleanup(func() { homeFolderLookupFunc = originalHomeFolderLookupFunc })
+
+	result, err := NamedHomeConfigDirProvider(".config").Locate("app.yml")
+	if err != nil || !reflect.DeepEqual([]string{"/home/me/.config/app.yml"}, result) {
+		t.Fatalf("expected provider to return [/home/me/.config/app.yml], but got %v, %v", result, err)
+	}
+}
+
+func TestProviderNames(t *testing.T) {
+	testDataSet := map[string]struct {
+		Provider            Provider
+		ExpectedName        string
+		ExpectedDescription string
+	}{
+		"working dir": {
+			Provider:            NamedWorkingDirProvider("conf"),
+			ExpectedName:        "working dir",
+			ExpectedDescription: "<working dir>/conf",
+		},
+		"executable dir": {
+			Provider:            NamedExecutableDirProvider(),
+			ExpectedName:        "executable dir",
+			ExpectedDescription: "<executable dir>",
+		},
+		"env var file path": {
+			Provider:            NamedEnvVarFilePathProvider("MYAPP_CONFIG"),
+			ExpectedName:        "env var MYAPP_CONFIG",
+			ExpectedDescription: "$MYAPP_CONFIG",
+		},
+		"home config dir": {
+			Provider:            NamedHomeConfigDirProvider(".config", "myapp"),
+			ExpectedName:        "home config dir",
+			ExpectedDescription: "~/.config/myapp",
+		},
+		"xdg config dirs": {
+			Provider:            XDGConfigDirsProvider("myapp"),
+			ExpectedName:        "XDG config dirs",
+			ExpectedDescription: "$XDG_CONFIG_DIRS/myapp",
+		},
+		"parent dirs": {
+			Provider:            ParentDirsSearchProvider(ParentDirsSearch{StartDir: "/a/b", SubFolders: []string{"conf"}}),
+			ExpectedName:        "parent dirs",
+			ExpectedDescription: "<parent dirs of /a/b>/conf",
+		},
+	}
+
+	for testCaseName, testData := range testDataSet {
+		t.Run(testCaseName, func(t *testing.T) {
+			if testData.ExpectedName != testData.Provider.Name() {
+				t.Fatalf("expected provider name '%s', but got '%s'", testData.ExpectedName, testData.Provider.Name())
+			}
+
+			if testData.ExpectedDescription != testData.Provider.Describe() {
+				t.Fatalf("expected provider description '%s', but got '%s'", testData.ExpectedDescription, testData.Provider.Describe())
+			}
+		})
+	}
+}
+
+func TestExplicitPathProvider(t *testing.T) {
+
+	result, err := ExplicitPathProvider("/etc/app/config.yml").Locate("app.yml")
+	if err != nil || !reflect.DeepEqual([]string{"/etc/app/config.yml"}, result) {
+		t.Fatalf("expected provider to return '/etc/app/config.yml', but got %v, %v", result, err)
+	}
+
+	result, err = ExplicitPathProvider("").Locate("app.yml")
+	if err != nil || len(result) != 0 {
+		t.Fatalf("expected provider to return no location for an empty path, but got %v, %v", result, err)
+	}
+}
+
+func TestSystemConfigDirProvider(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the default system config dirs are not absolute on windows")
+	}
+
+	originalExecutableDirProviderFunc := executableDirProviderFunc
+	executableDirProviderFunc = func() (string, error) { return "/opt/app/bin/app", nil }
+	t.Cleanup(func() { executableDirProviderFunc = originalExecutableDirProviderFunc })
+
+	result, err := SystemConfigDirProvider("app").Locate("app.yml")
+	if err != nil {
+		t.Fatalf("Did not expect provider to return an error, but got: %v", err)
+	}
+
+	expected := []string{
+		filepath.Join("/etc", "app", "app.yml"),
+		filepath.Join("/usr/local/etc", "app", "app.yml"),
+		filepath.Join("/opt/app/etc", "app", "app.yml"),
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("expected provider to return %v, but got %v", expected, result)
+	}
+}
+
+func TestSystemConfigDirPrefixesProvider(t *testing.T) {
+	originalExecutableDirProviderFunc := executableDirProviderFunc
+	executableDirProviderFunc = func() (string, error) { return "", errors.New("error-stub") }
+	t.Cleanup(func() { executableDirProviderFunc = originalExecutableDirProviderFunc })
+
+	prefix := filepath.Join(os.TempDir(), "srv", "etc")
+	expected := []string{filepath.Join(prefix, "app.yml")}
+
+	result, err := SystemConfigDirPrefixesProvider([]string{prefix}).Locate("app.yml")
+	if err != nil || !reflect.DeepEqual(expected, result) {
+		t.Fatalf("expected provider to return %v, but got %v, %v", expected, result, err)
+	}
+
+	result, err = SystemConfigDirPrefixesProvider([]string{prefix, "../etc"}).Locate("app.yml")
+	if err == nil || !reflect.DeepEqual(expected, result) {
+		t.Fatalf("expected provider to return %v and an error, but got %v, %v", expected, result, err)
+	}
+}
+
+func TestEnvVarDirProvider(t *testing.T) {
+	stubEnv(t, map[string]string{"MYAPP_HOME": "/opt/myapp", "EMPTY": ""})
+
+	expected := []string{filepath.Join("/opt/myapp", "conf", "app.yml")}
+
+	result, err := EnvVarDirProvider("MYAPP_HOME", "conf").Locate("app.yml")
+	if err != nil || !reflect.DeepEqual(expected, result) {
+		t.Fatalf("expected provider to return %v, but got %v, %v", expected, result, err)
+	}
+
+	for _, envVar := range []string{"EMPTY", "UNSET"} {
+		if _, err := EnvVarDirProvider(envVar).Locate("app.yml"); !errors.Is(err, ErrSkip) {
+			t.Fatalf("expected provider to be skipped for %s, but got: %v", envVar, err)
+		}
+	}
+}
+
+func TestPathListProvider(t *testing.T) {
+	separator := string(os.PathListSeparator)
+	stubEnv(t, map[string]string{"CONFIG_PATH": "/a" + separator + separator + "/b", "EMPTY": ""})
+
+	originalWorkingDirProviderFunc := workingDirProviderFunc
+	workingDirProviderFunc = func() (string, error) { return "/wd", nil }
+	t.Cleanup(func() { workingDirProviderFunc = originalWorkingDirProviderFunc })
+
+	testDataSet := map[string]struct {
+		List          PathList
+		ExpectedPaths []string
+	}{
+		"empty elements ignored": {
+			List:          PathList{EnvVar: "CONFIG_PATH", SubFolders: []string{"app"}},
+			ExpectedPaths: []string{filepath.Join("/a", "app", "app.yml"), filepath.Join("/b", "app", "app.yml")},
+		},
+		"empty elements are working dir": {
+			List:          PathList{EnvVar: "CONFIG_PATH", EmptyIsWorkingDir: true},
+			ExpectedPaths: []string{filepath.Join("/a", "app.yml"), filepath.Join("/wd", "app.yml"), filepath.Join("/b", "app.yml")},
+		},
+	}
+
+	for testName, testData := range testDataSet {
+		t.Run(testName, func(t *testing.T) {
+			result, err := PathListProvider(testData.List).Locate("app.yml")
+			if err != nil {
+				t.Fatalf("Did not expect provider to return an error, but got: %v", err)
+			}
+
+			if !reflect.DeepEqual(testData.ExpectedPaths, result) {
+				t.Fatalf("expected provider to return %v, but got %v", testData.ExpectedPaths, result)
+			}
+		})
+	}
+
+	for _, envVar := range []string{"EMPTY", "UNSET"} {
+		if _, err := PathListProvider(PathList{EnvVar: envVar}).Locate("app.yml"); !errors.Is(err, ErrSkip) {
+			t.Fatalf("expected provider to be skipped for %s, but got: %v", envVar, err)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package filediscovery
+
+import (
+	"os"
+)
+
+// Result describes a discovered file.
+type Result struct {
+	// Path is the path of the discovered file.
+	Path string
+	// FileName is the name of the discovered file.
+	FileName string
+	// Provider is the index of the FileLocationProvider that suggested the file location.
+	Provider int
+	// ProviderName is the name of the FileLocationProvider that suggested the file location.
+	// If the provider has no name, a generic name containing its index is used.
+	ProviderName string
+	// Info is the os.FileInfo of the discovered file.
+	Info os.FileInfo
+	// Checked lists the file locations that were checked before the file was discovered.
+	Checked []Attempt
+}
+
+func newResult(attempt Attempt, providerName string, info os.FileInfo, checked []Attempt) *Result {
+	return &Result{
+		Path:         attempt.Path,
+		FileName:     attempt.FileName,
+		Provider:     attempt.Provider,
+		ProviderName: providerName,
+		Info:         info,
+		Checked:      append([]Attempt(nil), checked...),
+	}
+}
@@ -0,0 +1,57 @@
+package filediscovery
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFileDiscovery_DiscoverResult(t *testing.T) {
+
+	fsys := fstest.MapFS{
+		"user/app.yml": &fstest.MapFile{Data: []byte("user")},
+	}
+
+	providers := []Provider{
+		FileLocationProvider(func(fileName string) (string, error) { return "project/" + fileName, nil }),
+		FileLocationsProvider(func(fileName string) ([]string, error) {
+			return []string{"system/" + fileName, "user/" + fileName}, nil
+		}),
+	}
+
+	result, err := NewWithProviders(providers, WithFS(fsys)).DiscoverResult("app.yml")
+	if err != nil {
+		t.Fatalf("did not expect discovery.DiscoverResult to return an error, but got: %v", err)
+	}
+
+	if result.Path != "user/app.yml" || result.FileName != "app.yml" {
+		t.Fatalf("expected result for 'user/app.yml', but got %+v", result)
+	}
+
+	if result.Provider != 1 || result.ProviderName != "provider 1" {
+		t.Fatalf("expected result to name provider 1, but got %v '%s'", result.Provider, result.ProviderName)
+	}
+
+	if result.Info == nil || result.Info.Size() != int64(len("user")) {
+		t.Fatalf("expected result to contain file info, but got %v", result.Info)
+	}
+
+	if len(result.Checked) != 2 || result.Checked[0].Path != "project/app.yml" || result.Checked[1].Path != "system/app.yml" {
+		t.Fatalf("expected result to list the locations checked before, but got %+v", result.Checked)
+	}
+}
+
+func TestFileDiscovery_DiscoverResult_ifFileNotFoundReturnsNotFoundError(t *testing.T) {
+
+	_, provider := newFileLocationProviderMock()
+
+	result, err := NewWithProviders([]Provider{provider}).DiscoverResult("app.yml")
+	if result != nil {
+		t.Fatalf("expected no result, but got %+v", result)
+	}
+
+	var notFoundErr *NotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Fatalf("expected error to be a %T, but got: %v", notFoundErr, err)
+	}
+}
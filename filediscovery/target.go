@@ -0,0 +1,22 @@
+package filediscovery
+
+// Target defines the kind of file system entries FileDiscovery accepts.
+type Target int
+
+const (
+	// TargetFile accepts files only.
+	TargetFile Target = iota
+	// TargetDirectory accepts directories only.
+	TargetDirectory
+	// TargetAny accepts files as well as directories.
+	TargetAny
+)
+
+// DiscoverDir works like Discover, but searches for a directory instead of a file, regardless of the Target option.
+// It can be used to locate plugin or template directories with the same FileLocationProviders.
+func (fd *FileDiscovery) DiscoverDir(dirName string) (string, error) {
+	dirDiscovery := *fd
+	dirDiscovery.target = TargetDirectory
+
+	return dirDiscovery.Discover(dirName)
+}
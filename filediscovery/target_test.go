@@ -0,0 +1,78 @@
+package filediscovery
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithTarget(t *testing.T) {
+
+	fsys := fstest.MapFS{
+		"a/plugins":      &fstest.MapFile{},
+		"b/plugins/x.so": &fstest.MapFile{},
+	}
+
+	providers := []Provider{
+		FileLocationsProvider(func(fileName string) ([]string, error) { return []string{"a/" + fileName, "b/" + fileName}, nil }),
+	}
+
+	testDataSet := map[string]struct {
+		Target       Target
+		ExpectedPath string
+	}{
+		"file":      {Target: TargetFile, ExpectedPath: "a/plugins"},
+		"directory": {Target: TargetDirectory, ExpectedPath: "b/plugins"},
+		"any":       {Target: TargetAny, ExpectedPath: "a/plugins"},
+	}
+
+	for testCaseName, testData := range testDataSet {
+		t.Run(testCaseName, func(t *testing.T) {
+			result, err := NewWithProviders(providers, WithFS(fsys), WithTarget(testData.Target)).Discover("plugins")
+			if err != nil {
+				t.Fatalf("did not expect discovery.Discover to return an error, but got: %v", err)
+			}
+
+			if testData.ExpectedPath != result {
+				t.Fatalf("expected '%s', but got '%s'", testData.ExpectedPath, result)
+			}
+		})
+	}
+}
+
+func TestFileDiscovery_DiscoverDir(t *testing.T) {
+
+	fsys := fstest.MapFS{
+		"a/templates":        &fstest.MapFile{},
+		"b/templates/x.tmpl": &fstest.MapFile{},
+		"c/templates/y.tmpl": &fstest.MapFile{},
+	}
+
+	providers := []Provider{
+		FileLocationsProvider(func(fileName string) ([]string, error) {
+			return []string{"a/" + fileName, "b/" + fileName, "c/" + fileName}, nil
+		}),
+	}
+
+	discovery := NewWithProviders(providers, WithFS(fsys))
+
+	result, err := discovery.DiscoverDir("templates")
+	if err != nil {
+		t.Fatalf("did not expect discovery.DiscoverDir to return an error, but got: %v", err)
+	}
+
+	if result != "b/templates" {
+		t.Fatalf("expected 'b/templates', but got '%s'", result)
+	}
+
+	var notFoundErr *NotFoundError
+	_, err = NewWithProviders(providers[:1], WithFS(fstest.MapFS{"a/templates": &fstest.MapFile{}})).DiscoverDir("templates")
+	if !errors.As(err, &notFoundErr) || !errors.Is(notFoundErr.Attempts[0].Err, ErrIsNotDirectory) {
+		t.Fatalf("expected file to be rejected as not a directory, but got: %v", err)
+	}
+
+	result, err = discovery.Discover("templates")
+	if err != nil || result != "a/templates" {
+		t.Fatalf("expected Discover to still search for files, but got '%s', %v", result, err)
+	}
+}
@@ -0,0 +1,104 @@
+package filediscovery
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+const defaultPollInterval = time.Second
+
+// EventKind defines how the discovery result of a watched file changed.
+type EventKind int
+
+const (
+	// EventFound means a file was discovered, where none could be found before.
+	EventFound EventKind = iota
+	// EventChanged means the discovered file was modified.
+	EventChanged
+	// EventReplaced means a different file location is discovered now, for example because a file appeared at a
+	// location of higher priority.
+	EventReplaced
+	// EventRemoved means the discovered file cannot be found anymore, nor any other file location.
+	EventRemoved
+)
+
+// Event describes a change of the discovery result of a watched file.
+type Event struct {
+	// Kind is the kind of change.
+	Kind EventKind
+	// Path is the path of the discovered file. It is empty for EventRemoved.
+	Path string
+	// Previous is the path of the file discovered before. It is empty for EventFound.
+	Previous string
+	// Info is the os.FileInfo of the discovered file. It is nil for EventRemoved.
+	Info os.FileInfo
+	// Err is the error of the discovery for EventRemoved.
+	Err error
+}
+
+// Watch discovers the given fileName repeatedly and sends an Event whenever the result differs from the result of
+// the previous discovery, starting with the result at the time Watch is called. The file locations are polled in the
+// interval given by WithPollInterval. The returned channel is closed when ctx is done.
+func (fd *FileDiscovery) Watch(ctx context.Context, fileName string) <-chan Event {
+	events := make(chan Event)
+	current, _ := fd.discoverFirst(ctx, fileName, fd.providerBatches(ctx, fileName))
+
+	go func() {
+		defer close(events)
+
+		interval := fd.pollInterval
+		if interval <= 0 {
+			interval = defaultPollInterval
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			result, err := fd.discoverFirst(ctx, fileName, fd.providerBatches(ctx, fileName))
+			if ctx.Err() != nil {
+				return
+			}
+
+			event, changed := compareResults(current, result, err)
+			if !changed {
+				continue
+			}
+
+			current = result
+
+			select {
+			case <-ctx.Done():
+				return
+			case events <- event:
+			}
+		}
+	}()
+
+	return events
+}
+
+// compareResults returns the Event describing the change from the previous to the current discovery result.
+func compareResults(previous *Result, current *Result, err error) (Event, bool) {
+	switch {
+	case previous == nil && current == nil:
+		return Event{}, false
+	case previous == nil:
+		return Event{Kind: EventFound, Path: current.Path, Info: current.Info}, true
+	case current == nil:
+		return Event{Kind: EventRemoved, Previous: previous.Path, Err: err}, true
+	case previous.Path != current.Path:
+		return Event{Kind: EventReplaced, Path: current.Path, Previous: previous.Path, Info: current.Info}, true
+	case !previous.Info.ModTime().Equal(current.Info.ModTime()) || previous.Info.Size() != current.Info.Size():
+		return Event{Kind: EventChanged, Path: current.Path, Previous: previous.Path, Info: current.Info}, true
+	}
+
+	return Event{}, false
+}
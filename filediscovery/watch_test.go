@@ -0,0 +1,106 @@
+package filediscovery
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+	"time"
+)
+
+func TestFileDiscovery_Watch(t *testing.T) {
+
+	testFilename := "test-file"
+	dirs := createTestDirs(t, 3)
+	defer removeTestDirs(t, dirs)
+
+	providers := []Provider{
+		FileLocationProvider(func(fileName string) (string, error) { return path.Join(dirs[0], fileName), nil }),
+		FileLocationProvider(func(fileName string) (string, error) { return path.Join(dirs[1], fileName), nil }),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := NewWithProviders(providers, WithPollInterval(time.Millisecond)).Watch(ctx, testFilename)
+
+	lowPriorityFile := path.Join(dirs[1], testFilename)
+	highPriorityFile := path.Join(dirs[0], testFilename)
+
+	placeTestFile(t, dirs[2], lowPriorityFile)
+	expectEvent(t, events, Event{Kind: EventFound, Path: lowPriorityFile})
+
+	modTime := time.Now().Add(time.Hour)
+	if err := os.Chtimes(lowPriorityFile, modTime, modTime); err != nil {
+		t.Fatalf("did not expect os.Chtimes to return an error, but got: %v", err)
+	}
+	expectEvent(t, events, Event{Kind: EventChanged, Path: lowPriorityFile, Previous: lowPriorityFile})
+
+	placeTestFile(t, dirs[2], highPriorityFile)
+	expectEvent(t, events, Event{Kind: EventReplaced, Path: highPriorityFile, Previous: lowPriorityFile})
+
+	removeTestFile(t, highPriorityFile)
+	expectEvent(t, events, Event{Kind: EventReplaced, Path: lowPriorityFile, Previous: highPriorityFile})
+
+	removeTestFile(t, lowPriorityFile)
+	expectEvent(t, events, Event{Kind: EventRemoved, Previous: lowPriorityFile})
+
+	cancel()
+
+	for range events {
+	}
+}
+
+func TestFileDiscovery_Watch_closesChannelWhenContextIsDone(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := NewWithProviders([]Provider{}, WithPollInterval(time.Millisecond)).Watch(ctx, "test-file")
+
+	cancel()
+
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Fatalf("expected no event, but got one")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected channel to be closed, but it was not")
+	}
+}
+
+func expectEvent(t *testing.T, events <-chan Event, expected Event) {
+	t.Helper()
+
+	select {
+	case event := <-events:
+		if event.Kind != expected.Kind || event.Path != expected.Path || event.Previous != expected.Previous {
+			t.Fatalf("expected event %+v, but got %+v", expected, event)
+		}
+
+		if (event.Info == nil) != (expected.Kind == EventRemoved) || (event.Err == nil) != (expected.Kind != EventRemoved) {
+			t.Fatalf("expected event %v to have either Info or Err, but got %+v", expected.Kind, event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected event %+v, but got none", expected)
+	}
+}
+
+// placeTestFile creates the test file in stagingDir and moves it to filePath, so it never is observed half written.
+func placeTestFile(t *testing.T, stagingDir string, filePath string) {
+	t.Helper()
+
+	stagedFile := path.Join(stagingDir, path.Base(filePath))
+	createTestFile(t, stagedFile)
+
+	if err := os.Rename(stagedFile, filePath); err != nil {
+		t.Fatalf("did not expect os.Rename to return an error, but got: %v", err)
+	}
+}
+
+func removeTestFile(t *testing.T, filePath string) {
+	t.Helper()
+
+	if err := os.Remove(filePath); err != nil {
+		t.Fatalf("did not expect os.Remove to return an error, but got: %v", err)
+	}
+}
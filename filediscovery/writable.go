@@ -0,0 +1,78 @@
+package filediscovery
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"time"
+)
+
+// ResolveWritable returns the first file location of the given fileName whose directory exists, or could be created,
+// and is writable. It is the counterpart of Discover, used to decide where to create a file that does not exist yet.
+// A missing directory is only created if the WithCreateDirs option is given, otherwise its nearest existing parent
+// directory must be writable. A location of an existing file is only accepted if the file itself is writable.
+// If no location is writable a *NotFoundError is returned.
+func (fd *FileDiscovery) ResolveWritable(fileName string) (string, error) {
+	ctx := context.Background()
+	start := time.Now()
+
+	attempts, found, abortErr := fd.search(ctx, fd.providerBatches(ctx, fileName), func(attempt *Attempt) bool {
+		checkStart := time.Now()
+		attempt.Err = fd.checkWritable(attempt.Path)
+		fd.observeCheck(attempt, nil, checkStart)
+
+		return attempt.Err == nil
+	})
+
+	if found < 0 {
+		err := &NotFoundError{FileName: fileName, Attempts: attempts, Err: abortErr}
+		fd.observeDecision(fileName, nil, err, start)
+
+		return "", err
+	}
+
+	fd.observeDecision(fileName, []string{attempts[found].Path}, nil, start)
+
+	return attempts[found].Path, nil
+}
+
+func (fd *FileDiscovery) checkWritable(filePath string) error {
+	if filePath == "" {
+		return &fs.PathError{Op: "resolve", Path: filePath, Err: fs.ErrInvalid}
+	}
+
+	if info, err := fd.fileSystem.Stat(filePath); err == nil {
+		if info.IsDir() {
+			return ErrIsDirectory
+		}
+
+		return fd.fileSystem.CheckWritable(filePath)
+	}
+
+	dir := filepath.Dir(filePath)
+
+	info, err := fd.fileSystem.Stat(dir)
+	if errors.Is(err, fs.ErrNotExist) && fd.createDirs {
+		if err := fd.fileSystem.MkdirAll(dir, fd.createDirsPerm); err != nil {
+			return err
+		}
+
+		return fd.fileSystem.CheckWritable(dir)
+	}
+
+	for errors.Is(err, fs.ErrNotExist) && filepath.Dir(dir) != dir {
+		dir = filepath.Dir(dir)
+		info, err = fd.fileSystem.Stat(dir)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return &fs.PathError{Op: "stat", Path: dir, Err: ErrIsNotDirectory}
+	}
+
+	return fd.fileSystem.CheckWritable(dir)
+}
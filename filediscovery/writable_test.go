@@ -0,0 +1,148 @@
+package filediscovery
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFileDiscovery_ResolveWritable(t *testing.T) {
+
+	if os.Getuid() == 0 {
+		t.Skip("directory permissions are not enforced for root")
+	}
+
+	dirs := createTestDirs(t, 1)
+	defer removeTestDirs(t, dirs)
+
+	readOnlyDir := filepath.Join(dirs[0], "read-only")
+	writableDir := filepath.Join(dirs[0], "writable")
+
+	for _, dir := range []string{readOnlyDir, writableDir} {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatalf("did not expect os.Mkdir to return an error, but got: %v", err)
+		}
+	}
+
+	if err := os.Chmod(readOnlyDir, 0555); err != nil {
+		t.Fatalf("did not expect os.Chmod to return an error, but got: %v", err)
+	}
+	defer func() { _ = os.Chmod(readOnlyDir, 0755) }()
+
+	errStub := errors.New("stub-error")
+	providers := []Provider{
+		FileLocationProvider(func(fileName string) (string, error) { return "", errStub }),
+		FileLocationProvider(func(fileName string) (string, error) { return filepath.Join(readOnlyDir, fileName), nil }),
+		FileLocationProvider(func(fileName string) (string, error) { return filepath.Join(writableDir, fileName), nil }),
+	}
+
+	result, err := NewWithProviders(providers).ResolveWritable("app.yml")
+	if err != nil {
+		t.Fatalf("did not expect discovery.ResolveWritable to return an error, but got: %v", err)
+	}
+
+	if filepath.Join(writableDir, "app.yml") != result {
+		t.Fatalf("expected '%s', but got '%s'", filepath.Join(writableDir, "app.yml"), result)
+	}
+
+	_, err = NewWithProviders(providers[:2]).ResolveWritable("app.yml")
+	if !errors.Is(err, os.ErrPermission) {
+		t.Fatalf("expected permission error, but got: %v", err)
+	}
+}
+
+func TestFileDiscovery_ResolveWritable_missingDirs(t *testing.T) {
+
+	dirs := createTestDirs(t, 1)
+	defer removeTestDirs(t, dirs)
+
+	missingDir := filepath.Join(dirs[0], "a", "b")
+	providers := []Provider{
+		FileLocationProvider(func(fileName string) (string, error) { return filepath.Join(missingDir, fileName), nil }),
+	}
+
+	result, err := NewWithProviders(providers).ResolveWritable("app.yml")
+	if err != nil || filepath.Join(missingDir, "app.yml") != result {
+		t.Fatalf("expected location with creatable directory to be resolved, but got '%s', %v", result, err)
+	}
+
+	if _, err := os.Stat(missingDir); !os.IsNotExist(err) {
+		t.Fatalf("expected directory not to be created without WithCreateDirs, but got: %v", err)
+	}
+
+	_, err = NewWithProviders(providers, WithCreateDirs(0700)).ResolveWritable("app.yml")
+	if err != nil {
+		t.Fatalf("did not expect discovery.ResolveWritable to return an error, but got: %v", err)
+	}
+
+	info, err := os.Stat(missingDir)
+	if err != nil || !info.IsDir() || info.Mode().Perm() != 0700 {
+		t.Fatalf("expected directory to be created with mode 0700, but got %v, %v", info, err)
+	}
+}
+
+func TestFileDiscovery_ResolveWritable_readOnlyFS(t *testing.T) {
+
+	providers := []Provider{
+		FileLocationProvider(func(fileName string) (string, error) { return "conf/" + fileName, nil }),
+	}
+
+	_, err := NewWithProviders(providers, WithFS(fstest.MapFS{"conf/other": &fstest.MapFile{}})).ResolveWritable("app.yml")
+	if !errors.Is(err, ErrNotWritable) {
+		t.Fatalf("expected %v, but got: %v", ErrNotWritable, err)
+	}
+}
+
+func TestFileDiscovery_ResolveWritable_rejectsEmptyPath(t *testing.T) {
+
+	providers := []Provider{
+		FileLocationProvider(func(fileName string) (string, error) { return "", nil }),
+	}
+
+	result, err := NewWithProviders(providers).ResolveWritable("app.yml")
+	if result != "" || !errors.Is(err, fs.ErrInvalid) {
+		t.Fatalf("expected empty location to be rejected with %v, but got '%s', %v", fs.ErrInvalid, result, err)
+	}
+}
+
+func TestFileDiscovery_ResolveWritable_readOnlyFile(t *testing.T) {
+
+	if os.Getuid() == 0 {
+		t.Skip("file permissions are not enforced for root")
+	}
+
+	dirs := createTestDirs(t, 2)
+	defer removeTestDirs(t, dirs)
+
+	readOnlyFile := filepath.Join(dirs[0], "app.yml")
+	createTestFile(t, readOnlyFile)
+
+	if err := os.Chmod(readOnlyFile, 0444); err != nil {
+		t.Fatalf("did not expect os.Chmod to return an error, but got: %v", err)
+	}
+
+	writableFile := filepath.Join(dirs[1], "app.yml")
+	createTestFile(t, writableFile)
+
+	providers := []Provider{
+		FileLocationProvider(func(fileName string) (string, error) { return filepath.Join(dirs[0], fileName), nil }),
+		FileLocationProvider(func(fileName string) (string, error) { return filepath.Join(dirs[1], fileName), nil }),
+	}
+
+	result, err := NewWithProviders(providers).ResolveWritable("app.yml")
+	if err != nil {
+		t.Fatalf("did not expect discovery.ResolveWritable to return an error, but got: %v", err)
+	}
+
+	if writableFile != result {
+		t.Fatalf("expected '%s', but got '%s'", writableFile, result)
+	}
+
+	_, err = NewWithProviders(providers[:1]).ResolveWritable("app.yml")
+	if !errors.Is(err, os.ErrPermission) {
+		t.Fatalf("expected permission error, but got: %v", err)
+	}
+}